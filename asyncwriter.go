@@ -0,0 +1,332 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"io"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+)
+
+// OverflowPolicy determines what an AsyncWriter does with an entry written
+// while its queue is full.
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock waits for space in the queue, so no entries are lost
+	// but logging may stall behind a slow output.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest discards the entry being written.
+	OverflowDropNewest
+
+	// OverflowDropOldest discards the oldest queued entry with the lowest
+	// level, so that more severe entries are kept in preference to less
+	// severe ones. If the entry being written has a lower level than
+	// everything queued, it is discarded instead.
+	OverflowDropOldest
+)
+
+// DefaultAsyncQueueSize is the number of entries an AsyncWriter queues if no
+// size is given.
+const DefaultAsyncQueueSize = 1024
+
+// AsyncWriterOptions can be used to configure a new AsyncWriter.
+type AsyncWriterOptions struct {
+	// QueueSize is the maximum number of entries waiting to be written.
+	// Defaults to DefaultAsyncQueueSize.
+	QueueSize int
+
+	// Overflow is what to do when an entry is written with the queue full.
+	Overflow OverflowPolicy
+}
+
+// Make sure that AsyncWriter is a LevelWriter and Flushable
+var (
+	_ LevelWriter = &AsyncWriter{}
+	_ Flushable   = &AsyncWriter{}
+)
+
+// AsyncWriter queues entries in memory and writes them to another io.Writer
+// from a background goroutine. Using one as LoggerOptions.Output means a slow
+// output no longer stalls every goroutine that logs, as the logger's mutex is
+// only held while an entry is formatted and queued.
+//
+// Call Flush to wait for queued entries to be written, and Close to drain the
+// queue and stop the background goroutine on shutdown. Entries written after
+// Close are written directly to the underlying writer.
+type AsyncWriter struct {
+	w        io.Writer
+	overflow OverflowPolicy
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+
+	// queue is a ring buffer of n entries starting at head.
+	queue []asyncEntry
+	head  int
+	n     int
+
+	writing bool
+	closed  bool
+	err     error
+	done    chan struct{}
+
+	dropped map[Level]uint64
+}
+
+type asyncEntry struct {
+	level Level
+	p     []byte
+}
+
+// NewAsyncWriter returns an AsyncWriter which writes to w and starts its
+// background goroutine. If w is a LevelWriter, entries are written to it with
+// their level.
+func NewAsyncWriter(w io.Writer, opts *AsyncWriterOptions) *AsyncWriter {
+	if opts == nil {
+		opts = &AsyncWriterOptions{}
+	}
+
+	size := opts.QueueSize
+	if size <= 0 {
+		size = DefaultAsyncQueueSize
+	}
+
+	a := &AsyncWriter{
+		w:        w,
+		overflow: opts.Overflow,
+		queue:    make([]asyncEntry, size),
+		done:     make(chan struct{}),
+		dropped:  make(map[Level]uint64),
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+	a.idle = sync.NewCond(&a.mu)
+
+	go a.run()
+
+	return a
+}
+
+// Write queues p to be written with NoLevel.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	return a.LevelWrite(NoLevel, p)
+}
+
+// LevelWrite queues p to be written, applying the overflow policy if the
+// queue is full. It never returns an error from the underlying writer, those
+// are returned by Flush and Close.
+func (a *AsyncWriter) LevelWrite(level Level, p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return a.writeClosed(asyncEntry{level: level, p: p})
+	}
+
+	if a.n == len(a.queue) {
+		switch a.overflow {
+		case OverflowDropNewest:
+			a.dropped[level]++
+			return len(p), nil
+		case OverflowDropOldest:
+			if !a.dropLowest(level) {
+				a.dropped[level]++
+				return len(p), nil
+			}
+		default:
+			for a.n == len(a.queue) && !a.closed {
+				a.notFull.Wait()
+			}
+
+			if a.closed {
+				return a.writeClosed(asyncEntry{level: level, p: p})
+			}
+		}
+	}
+
+	// The caller is free to reuse p once we return.
+	a.queue[(a.head+a.n)%len(a.queue)] = asyncEntry{
+		level: level,
+		p:     append([]byte(nil), p...),
+	}
+	a.n++
+
+	a.notEmpty.Signal()
+
+	return len(p), nil
+}
+
+// dropLowest removes the oldest queued entry with the lowest level, provided
+// that level is no higher than the given one, and reports whether an entry
+// was removed.
+func (a *AsyncWriter) dropLowest(level Level) bool {
+	idx := -1
+	for i := 0; i < a.n; i++ {
+		e := a.queue[(a.head+i)%len(a.queue)]
+		if e.level <= level && (idx == -1 || e.level < a.queue[(a.head+idx)%len(a.queue)].level) {
+			idx = i
+		}
+	}
+
+	if idx == -1 {
+		return false
+	}
+
+	a.dropped[a.queue[(a.head+idx)%len(a.queue)].level]++
+
+	// Close the gap, keeping the remaining entries in order.
+	for i := idx; i < a.n-1; i++ {
+		a.queue[(a.head+i)%len(a.queue)] = a.queue[(a.head+i+1)%len(a.queue)]
+	}
+	a.n--
+	a.queue[(a.head+a.n)%len(a.queue)] = asyncEntry{}
+
+	return true
+}
+
+// run writes queued entries until the writer is closed and the queue empty.
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+
+	batch := make([]asyncEntry, 0, len(a.queue))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for {
+		for a.n == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+
+		if a.n == 0 {
+			return
+		}
+
+		// Take everything queued so the writes happen without the lock.
+		batch = batch[:0]
+		for ; a.n > 0; a.n-- {
+			batch = append(batch, a.queue[a.head])
+			a.queue[a.head] = asyncEntry{}
+			a.head = (a.head + 1) % len(a.queue)
+		}
+		a.writing = true
+		a.notFull.Broadcast()
+		a.mu.Unlock()
+
+		var err error
+		for _, e := range batch {
+			if _, werr := a.write(e); werr != nil && err == nil {
+				err = werr
+			}
+		}
+
+		a.mu.Lock()
+		a.writing = false
+		if err != nil && a.err == nil {
+			a.err = err
+		}
+		a.idle.Broadcast()
+	}
+}
+
+func (a *AsyncWriter) write(e asyncEntry) (int, error) {
+	if lw, ok := a.w.(LevelWriter); ok {
+		return lw.LevelWrite(e.level, e.p)
+	}
+	return a.w.Write(e.p)
+}
+
+// writeClosed writes directly to the underlying writer once closed, after
+// waiting for the background goroutine to finish with the queue. The mutex
+// must be held, so that these writes don't interleave.
+func (a *AsyncWriter) writeClosed(e asyncEntry) (int, error) {
+	for a.n > 0 || a.writing {
+		a.idle.Wait()
+	}
+
+	return a.write(e)
+}
+
+// Flush waits until every entry queued before the call has been written, and
+// then flushes the underlying writer if it is Flushable. It returns the first
+// error encountered writing since the last call to Flush.
+func (a *AsyncWriter) Flush() error {
+	a.mu.Lock()
+	for a.n > 0 || a.writing {
+		a.idle.Wait()
+	}
+	err := a.err
+	a.err = nil
+	a.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if f, ok := a.w.(Flushable); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// Close drains the queue, stops the background goroutine and flushes the
+// underlying writer. The underlying writer is not closed.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	a.closed = true
+	a.notEmpty.Signal()
+	a.notFull.Broadcast()
+	a.mu.Unlock()
+
+	<-a.done
+
+	return a.Flush()
+}
+
+// Dropped returns the total number of entries discarded because the queue
+// was full.
+func (a *AsyncWriter) Dropped() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var total uint64
+	for _, n := range a.dropped {
+		total += n
+	}
+
+	return total
+}
+
+// DroppedByLevel returns the number of entries discarded because the queue
+// was full, by the level of the discarded entry.
+func (a *AsyncWriter) DroppedByLevel() map[Level]uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	dropped := make(map[Level]uint64, len(a.dropped))
+	for level, n := range a.dropped {
+		dropped[level] = n
+	}
+
+	return dropped
+}
+
+// SupportsColor implements SupportsColor so that AutoColor can see through
+// to the underlying writer.
+func (a *AsyncWriter) SupportsColor() bool {
+	switch w := a.w.(type) {
+	case SupportsColor:
+		return w.SupportsColor()
+	case interface{ Fd() uintptr }:
+		return isatty.IsTerminal(w.Fd()) || isatty.IsCygwinTerminal(w.Fd())
+	default:
+		return false
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedWriter blocks each write until the gate is opened, signalling on
+// started whenever a write begins.
+type gatedWriter struct {
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+	err error
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{
+		started: make(chan struct{}, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	g.started <- struct{}{}
+	<-g.gate
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return 0, g.err
+	}
+
+	return g.buf.Write(p)
+}
+
+func (g *gatedWriter) open() {
+	g.once.Do(func() { close(g.gate) })
+}
+
+func (g *gatedWriter) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	t.Run("writes queued entries in order", func(t *testing.T) {
+		var buf lockedBuffer
+
+		aw := NewAsyncWriter(&buf, nil)
+
+		logger := New(&LoggerOptions{
+			Output:      aw,
+			DisableTime: true,
+		})
+
+		logger.Info("one")
+		logger.Warn("two")
+		logger.Error("three")
+
+		require.NoError(t, aw.Flush())
+		assert.Equal(t, "[INFO]  one\n[WARN]  two\n[ERROR] three\n", buf.String())
+
+		require.NoError(t, aw.Close())
+
+		// Entries written once closed go straight to the output.
+		logger.Info("four")
+		assert.Equal(t, "[INFO]  one\n[WARN]  two\n[ERROR] three\n[INFO]  four\n", buf.String())
+	})
+
+	t.Run("blocks when the queue is full", func(t *testing.T) {
+		gw := newGatedWriter()
+		aw := NewAsyncWriter(gw, &AsyncWriterOptions{QueueSize: 2})
+
+		_, _ = aw.LevelWrite(Info, []byte("a"))
+		<-gw.started
+
+		_, _ = aw.LevelWrite(Info, []byte("b"))
+		_, _ = aw.LevelWrite(Info, []byte("c"))
+
+		written := make(chan struct{})
+		go func() {
+			_, _ = aw.LevelWrite(Info, []byte("d"))
+			close(written)
+		}()
+
+		select {
+		case <-written:
+			t.Fatal("write did not block")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		gw.open()
+		<-written
+
+		require.NoError(t, aw.Close())
+		assert.Equal(t, "abcd", gw.String())
+		assert.Equal(t, uint64(0), aw.Dropped())
+	})
+
+	t.Run("drops the newest entry", func(t *testing.T) {
+		gw := newGatedWriter()
+		aw := NewAsyncWriter(gw, &AsyncWriterOptions{
+			QueueSize: 2,
+			Overflow:  OverflowDropNewest,
+		})
+
+		_, _ = aw.LevelWrite(Info, []byte("a"))
+		<-gw.started
+
+		_, _ = aw.LevelWrite(Info, []byte("b"))
+		_, _ = aw.LevelWrite(Info, []byte("c"))
+		_, _ = aw.LevelWrite(Error, []byte("d"))
+
+		gw.open()
+		require.NoError(t, aw.Close())
+
+		assert.Equal(t, "abc", gw.String())
+		assert.Equal(t, uint64(1), aw.Dropped())
+		assert.Equal(t, map[Level]uint64{Error: 1}, aw.DroppedByLevel())
+	})
+
+	t.Run("drops the oldest entry with the lowest level", func(t *testing.T) {
+		gw := newGatedWriter()
+		aw := NewAsyncWriter(gw, &AsyncWriterOptions{
+			QueueSize: 3,
+			Overflow:  OverflowDropOldest,
+		})
+
+		_, _ = aw.LevelWrite(Info, []byte("a"))
+		<-gw.started
+
+		_, _ = aw.LevelWrite(Error, []byte("b"))
+		_, _ = aw.LevelWrite(Info, []byte("c"))
+		_, _ = aw.LevelWrite(Info, []byte("d"))
+
+		// Replaces c, the oldest of the lowest level.
+		_, _ = aw.LevelWrite(Warn, []byte("e"))
+
+		// Nothing queued is less severe, so this is dropped itself.
+		_, _ = aw.LevelWrite(Debug, []byte("f"))
+
+		gw.open()
+		require.NoError(t, aw.Close())
+
+		assert.Equal(t, "abde", gw.String())
+		assert.Equal(t, uint64(2), aw.Dropped())
+		assert.Equal(t, map[Level]uint64{Info: 1, Debug: 1}, aw.DroppedByLevel())
+	})
+
+	t.Run("reports write errors from Flush", func(t *testing.T) {
+		gw := newGatedWriter()
+		gw.err = errors.New("disk full")
+		gw.open()
+
+		aw := NewAsyncWriter(gw, nil)
+
+		n, err := aw.Write([]byte("a"))
+		assert.Equal(t, 1, n)
+		assert.NoError(t, err)
+
+		assert.EqualError(t, aw.Flush(), "disk full")
+		assert.NoError(t, aw.Flush())
+		assert.NoError(t, aw.Close())
+	})
+
+	t.Run("flushes the underlying writer", func(t *testing.T) {
+		var buf bufferingBuffer
+
+		aw := NewAsyncWriter(&buf, nil)
+		defer func() { _ = aw.Close() }()
+
+		logger := New(&LoggerOptions{
+			Output:      aw,
+			DisableTime: true,
+		})
+		logger.Info("hello")
+
+		var second bytes.Buffer
+		require.NoError(t, logger.(OutputResettable).ResetOutputWithFlush(&LoggerOptions{
+			Output: &second,
+		}, aw))
+
+		assert.Equal(t, "[INFO]  hello\n", buf.String())
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-hclog/internal/callerpath"
+)
+
+// entryWriter writes decoded entries to a Logger as if they had been logged
+// to it, keeping the time, name and caller of each.
+type entryWriter struct {
+	logger hclog.Logger
+	json   bool
+
+	// t is the time of the entry being written, returned by the TimeFn of
+	// the logger.
+	t time.Time
+}
+
+// newEntryWriter returns an entryWriter which writes with a Logger made
+// from opts. The caller of each entry is written where the logger would
+// write its own, so opts shouldn't set IncludeLocation.
+func newEntryWriter(opts *hclog.LoggerOptions) *entryWriter {
+	w := &entryWriter{json: opts.JSONFormat}
+
+	opts.Level = hclog.Trace
+	opts.TimeFn = func() time.Time { return w.t }
+	w.logger = hclog.New(opts)
+
+	return w
+}
+
+// write writes e. Entries with NoLevel, which aren't entries of the
+// logger, aren't written.
+func (w *entryWriter) write(e *hclog.Entry) {
+	if e.Level == hclog.NoLevel {
+		return
+	}
+
+	w.t = e.Time
+
+	name, msg := e.Name, e.Message
+
+	args := e.Args
+	if e.Stacktrace != "" {
+		args = append(args[:len(args):len(args)], e.Stacktrace)
+	}
+
+	logger := w.logger
+
+	switch {
+	case e.Caller == "":
+	case w.json:
+		logger = logger.With("@caller", e.Caller)
+	case name != "":
+		name = callerpath.Trim(e.Caller) + ": " + name
+	default:
+		msg = callerpath.Trim(e.Caller) + ": " + msg
+	}
+
+	logger.ResetNamed(name).Log(e.Level, msg, args...)
+}
@@ -0,0 +1,507 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// A filter reports whether an entry matches a query expression.
+type filter func(e *hclog.Entry) bool
+
+// parseFilter parses a query expression such as
+//
+//	level>=warn && module~"^raft" && duration_ms>500
+//
+// Comparisons are joined with && and ||, negated with !, and grouped with
+// parentheses. The left hand side of a comparison is level, module (or
+// name), msg (or message), caller, time, or the key of an arg; a key on its
+// own matches entries which have that arg. The operators are ==, !=, <, <=,
+// >, >=, ~ and !~ for regular expressions, which match anywhere in the value
+// unless anchored with ^ and $, ^= for a prefix and *= for a substring. An
+// empty expression matches every entry.
+func parseFilter(s string) (filter, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	if p.peek().kind == tokEOF {
+		return func(*hclog.Entry) bool { return true }, nil
+	}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// symbols are the tokens made of punctuation, longest first so that "!="
+// isn't read as "!" followed by "=".
+var symbols = []struct {
+	text string
+	kind tokenKind
+}{
+	{"&&", tokAnd},
+	{"||", tokOr},
+	{"==", tokOp},
+	{"!=", tokOp},
+	{"<=", tokOp},
+	{">=", tokOp},
+	{"!~", tokOp},
+	{"^=", tokOp},
+	{"*=", tokOp},
+	{"<", tokOp},
+	{">", tokOp},
+	{"~", tokOp},
+	{"!", tokNot},
+	{"(", tokLParen},
+	{")", tokRParen},
+}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+
+	i := 0
+
+outer:
+	for i < len(s) {
+		if s[i] == ' ' || s[i] == '\t' || s[i] == '\n' {
+			i++
+			continue
+		}
+
+		for _, sym := range symbols {
+			if strings.HasPrefix(s[i:], sym.text) {
+				toks = append(toks, token{kind: sym.kind, text: sym.text, pos: i})
+				i += len(sym.text)
+				continue outer
+			}
+		}
+
+		if s[i] == '"' {
+			str, err := strconv.QuotedPrefix(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+
+			text, _ := strconv.Unquote(str)
+			toks = append(toks, token{kind: tokString, text: text, pos: i})
+			i += len(str)
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isWordEnd(s[i:]) {
+			i++
+		}
+
+		if i == start {
+			return nil, fmt.Errorf("unexpected %q at offset %d", s[i], i)
+		}
+
+		toks = append(toks, token{kind: tokWord, text: s[start:i], pos: start})
+	}
+
+	return append(toks, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+// isWordEnd reports whether a bare word ends at the start of s.
+func isWordEnd(s string) bool {
+	switch s[0] {
+	case ' ', '\t', '\n', '"', '&', '|', '=', '!', '<', '>', '~', '(', ')':
+		return true
+	case '^', '*':
+		return len(s) > 1 && s[1] == '='
+	default:
+		return false
+	}
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (filter, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokOr {
+		p.next()
+
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		lhs := f
+		f = func(e *hclog.Entry) bool { return lhs(e) || rhs(e) }
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseAnd() (filter, error) {
+	f, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokAnd {
+		p.next()
+
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		lhs := f
+		f = func(e *hclog.Entry) bool { return lhs(e) && rhs(e) }
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseUnary() (filter, error) {
+	switch t := p.next(); t.kind {
+	case tokNot:
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *hclog.Entry) bool { return !f(e) }, nil
+
+	case tokLParen:
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if t := p.next(); t.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\" at offset %d, found %s", t.pos, t)
+		}
+		return f, nil
+
+	case tokWord, tokString:
+		if p.peek().kind != tokOp {
+			key := t.text
+			return func(e *hclog.Entry) bool {
+				_, ok := lookupArg(e.Args, key)
+				return ok
+			}, nil
+		}
+
+		op := p.next()
+
+		val := p.next()
+		if val.kind != tokWord && val.kind != tokString {
+			return nil, fmt.Errorf("expected a value after %q at offset %d, found %s", op.text, val.pos, val)
+		}
+
+		f, err := comparison(t.text, op.text, val.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s at offset %d", err, t.pos)
+		}
+		return f, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+}
+
+// comparison returns a filter for the comparison "key op val".
+func comparison(key, op, val string) (filter, error) {
+	switch key {
+	case "level":
+		return levelComparison(op, val)
+	case "time":
+		return timeComparison(op, val)
+	case "module", "name":
+		match, err := stringComparison(op, val)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *hclog.Entry) bool { return e.Level != hclog.NoLevel && match(e.Name) }, nil
+	case "msg", "message":
+		match, err := stringComparison(op, val)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *hclog.Entry) bool { return match(e.Message) }, nil
+	case "caller":
+		match, err := stringComparison(op, val)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *hclog.Entry) bool { return e.Caller != "" && match(e.Caller) }, nil
+	default:
+		match, err := valueComparison(op, val)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *hclog.Entry) bool {
+			v, ok := lookupArg(e.Args, key)
+			return ok && match(v)
+		}, nil
+	}
+}
+
+func levelComparison(op, val string) (filter, error) {
+	level := hclog.LevelFromString(val)
+	if level == hclog.NoLevel {
+		return nil, fmt.Errorf("unknown level %q", val)
+	}
+
+	satisfies, err := ordered(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(e *hclog.Entry) bool {
+		if e.Level == hclog.NoLevel {
+			return false
+		}
+		return satisfies(cmp.Compare(e.Level, level))
+	}, nil
+}
+
+// timeLayouts are the layouts tried when parsing a time in an expression.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	hclog.TimeFormat,
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	time.DateOnly,
+}
+
+func timeComparison(op, val string) (filter, error) {
+	var (
+		t   time.Time
+		err error
+	)
+
+	for _, layout := range timeLayouts {
+		if t, err = time.Parse(layout, val); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q", val)
+	}
+
+	satisfies, err := ordered(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(e *hclog.Entry) bool {
+		return !e.Time.IsZero() && satisfies(e.Time.Compare(t))
+	}, nil
+}
+
+// ordered returns a function which reports whether the result of comparing
+// two values, as returned by cmp.Compare, satisfies op.
+func ordered(op string) (func(c int) bool, error) {
+	switch op {
+	case "==":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=":
+		return func(c int) bool { return c != 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	default:
+		return nil, fmt.Errorf("operator %q can't be used here", op)
+	}
+}
+
+func stringComparison(op, val string) (func(s string) bool, error) {
+	switch op {
+	case "~", "!~":
+		re, err := regexp.Compile(val)
+		if err != nil {
+			return nil, err
+		}
+
+		want := op == "~"
+		return func(s string) bool { return re.MatchString(s) == want }, nil
+	case "^=":
+		return func(s string) bool { return strings.HasPrefix(s, val) }, nil
+	case "*=":
+		return func(s string) bool { return strings.Contains(s, val) }, nil
+	}
+
+	satisfies, err := ordered(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(s string) bool { return satisfies(strings.Compare(s, val)) }, nil
+}
+
+// valueComparison compares the value of an arg as a number or duration when
+// val is one, and as text otherwise. Values which aren't a number or
+// duration never satisfy an ordered comparison with one, but are compared
+// as text by the other operators.
+func valueComparison(op, val string) (func(v any) bool, error) {
+	match, err := stringComparison(op, val)
+	if err != nil {
+		return nil, err
+	}
+
+	satisfies, err := ordered(op)
+	if err != nil {
+		return func(v any) bool { return match(valueText(v)) }, nil
+	}
+
+	num, numErr := strconv.ParseFloat(val, 64)
+	dur, durErr := time.ParseDuration(val)
+
+	inequality := op != "==" && op != "!="
+
+	return func(v any) bool {
+		s := valueText(v)
+
+		if numErr == nil {
+			if n, err := strconv.ParseFloat(s, 64); err == nil {
+				return satisfies(cmp.Compare(n, num))
+			}
+		}
+
+		if durErr == nil {
+			if d, err := time.ParseDuration(s); err == nil {
+				return satisfies(cmp.Compare(d, dur))
+			}
+		}
+
+		if inequality && (numErr == nil || durErr == nil) {
+			return false
+		}
+
+		return match(s)
+	}, nil
+}
+
+// lookupArg returns the value of the arg with the given key. Keys are
+// dotted to look inside JSON objects, as they are in the plain format.
+func lookupArg(args []any, key string) (any, bool) {
+	for i := 0; i+1 < len(args); i += 2 {
+		k, _ := args[i].(string)
+		if k == key {
+			return args[i+1], true
+		}
+
+		if obj, ok := args[i+1].(map[string]any); ok && strings.HasPrefix(key, k+".") {
+			if v, ok := lookupMember(obj, key[len(k)+1:]); ok {
+				return v, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func lookupMember(obj map[string]any, key string) (any, bool) {
+	if v, ok := obj[key]; ok {
+		return v, true
+	}
+
+	for k, v := range obj {
+		if inner, ok := v.(map[string]any); ok && strings.HasPrefix(key, k+".") {
+			if v, ok := lookupMember(inner, key[len(k)+1:]); ok {
+				return v, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// valueText returns the text of a decoded value, with objects and arrays
+// as JSON.
+func valueText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	entry := &hclog.Entry{
+		Time:    now,
+		Level:   hclog.Warn,
+		Name:    "raft.snapshot",
+		Message: "snapshot took too long",
+		Caller:  "raft/snapshot.go:42",
+		Args: []any{
+			"duration_ms", json.Number("750"),
+			"took", "1.5s",
+			"peer", "10.0.0.1:8300",
+			"body", "one\ntwo",
+			"status", "abc",
+			"req", map[string]any{"id": json.Number("7"), "tags": map[string]any{"env": "prod"}},
+		},
+	}
+
+	other := &hclog.Entry{Level: hclog.NoLevel, Message: "panic: oops"}
+
+	cases := []struct {
+		expr  string
+		entry bool
+		other bool
+	}{
+		{expr: "", entry: true, other: true},
+		{expr: "level>=warn", entry: true},
+		{expr: "level>warn"},
+		{expr: "level==WARN", entry: true},
+		{expr: "level<warn"},
+		{expr: `module~"raft.*"`, entry: true},
+		{expr: `module~"^snapshot"`},
+		{expr: `module~"snap"`, entry: true},
+		{expr: `module~"^raft$"`},
+		{expr: "module^=raft", entry: true},
+		{expr: "module==raft"},
+		{expr: "module!=raft", entry: true},
+		{expr: `msg*="too long"`, entry: true},
+		{expr: `message~"^panic"`, other: true},
+		{expr: `msg!~"^panic"`, entry: true},
+		{expr: "caller*=snapshot.go", entry: true},
+		{expr: "time>=2024-05-06T07:00:00Z && time<2024-05-06", entry: false},
+		{expr: "time>=2024-05-06T07:00:00Z && time<2024-05-07", entry: true},
+		{expr: `time=="2024-05-06 07:08:09"`, entry: true},
+		{expr: "duration_ms>500", entry: true},
+		{expr: "duration_ms>1000"},
+		{expr: "duration_ms==750.0", entry: true},
+		{expr: "took>=1s", entry: true},
+		{expr: "took<500ms"},
+		{expr: "status>500"},
+		{expr: "status<=500"},
+		{expr: "status>=1s"},
+		{expr: "status!=500", entry: true},
+		{expr: "status>aaa", entry: true},
+		{expr: `peer=="10.0.0.1:8300"`, entry: true},
+		{expr: `body*="two"`, entry: true},
+		{expr: "req.id==7", entry: true},
+		{expr: "req.tags.env==prod", entry: true},
+		{expr: `req*="env"`, entry: true},
+		{expr: "missing!=1"},
+		{expr: "body", entry: true},
+		{expr: "!body", other: true},
+		{expr: "level>=warn && module~\"raft.*\" && duration_ms>500", entry: true},
+		{expr: "level>=error || msg*=panic", other: true},
+		{expr: "!(level>=error || msg*=panic)", entry: true},
+		{expr: "level>=warn && (took>1m || req.id==7)", entry: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			f, err := parseFilter(c.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, c.entry, f(entry), "entry")
+			assert.Equal(t, c.other, f(other), "other line")
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	cases := map[string]string{
+		"level>=":          `expected a value after ">=" at offset 7, found end of expression`,
+		"level>=loud":      `unknown level "loud" at offset 0`,
+		"time>yesterday":   `invalid time "yesterday" at offset 0`,
+		"level~warn":       `operator "~" can't be used here at offset 0`,
+		`msg~"("`:          "error parsing regexp: missing closing ): `(` at offset 0",
+		"(level>=warn":     `expected ")" at offset 12, found end of expression`,
+		"level>=warn warn": `unexpected "warn" at offset 12`,
+		`msg=="open`:       "unterminated string at offset 5",
+		"&& level>=warn":   `unexpected "&&" at offset 0`,
+	}
+
+	for expr, want := range cases {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseFilter(expr)
+			assert.EqualError(t, err, want)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+// Command hclog works with the output of hclog loggers.
+//
+// Usage:
+//
+//	hclog pretty [flags] [file ...]
+//	hclog query [flags] expression [file ...]
+//
+// Run "hclog <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command given by args, and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	switch args[0] {
+	case "pretty":
+		return runPretty(args[1:], stdin, stdout, stderr)
+	case "query":
+		return runQuery(args[1:], stdin, stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "hclog: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: hclog <command> [flags] [file ...]
+
+Commands:
+  pretty  render JSON log entries in the colored plain format
+  query   write the log entries which match an expression
+
+Files default to standard input, which can also be given as "-".
+`)
+}
+
+// eachInput calls fn with each of the named files, or stdin if there are
+// none. Files which can't be opened are reported to stderr, and false is
+// returned if there were any.
+func eachInput(cmd string, names []string, stdin io.Reader, stderr io.Writer, fn func(io.Reader) error) bool {
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	ok := true
+
+	for _, name := range names {
+		if name == "-" {
+			if err := fn(stdin); err != nil {
+				fmt.Fprintf(stderr, "hclog %s: %v\n", cmd, err)
+				ok = false
+			}
+			continue
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "hclog %s: %v\n", cmd, err)
+			ok = false
+			continue
+		}
+
+		err = fn(f)
+		_ = f.Close()
+
+		if err != nil {
+			fmt.Fprintf(stderr, "hclog %s: %s: %v\n", cmd, name, err)
+			ok = false
+		}
+	}
+
+	return ok
+}
@@ -0,0 +1,145 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// runPretty renders the JSON entries of its inputs in the plain format, as
+// written by a Logger with the same name, time and caller. Other lines,
+// including entries already in the plain format, are written as they are.
+func runPretty(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("pretty", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hclog pretty [flags] [file ...]")
+		fs.PrintDefaults()
+	}
+
+	var (
+		colorFlag  = fs.String("color", "auto", "when to color the output: auto, always or never")
+		headerOnly = fs.Bool("header-only", false, "color only the time, level and caller of each entry")
+		relative   = fs.Bool("relative", false, "write the time of each entry relative to the first")
+		timeFormat = fs.String("time-format", hclog.TimeFormat, "the format of the time of each entry")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var color hclog.ColorOption
+	switch *colorFlag {
+	case "auto":
+		color = hclog.AutoColor
+	case "always":
+		color = hclog.ForceColor
+	case "never":
+		color = hclog.ColorOff
+	default:
+		fmt.Fprintf(stderr, "hclog pretty: invalid -color %q\n", *colorFlag)
+		return 2
+	}
+
+	p := &prettyPrinter{
+		out: stdout,
+		entries: newEntryWriter(&hclog.LoggerOptions{
+			Output:               stdout,
+			Color:                color,
+			ColorHeaderOnly:      *headerOnly,
+			ColorHeaderAndFields: !*headerOnly,
+			TimeFormat:           *timeFormat,
+			DisableTime:          *relative,
+		}),
+		relative: *relative,
+	}
+
+	if !eachInput("pretty", fs.Args(), stdin, stderr, p.print) {
+		return 1
+	}
+
+	return 0
+}
+
+type prettyPrinter struct {
+	out     io.Writer
+	entries *entryWriter
+
+	relative bool
+	start    time.Time
+}
+
+// print renders the entries read from r.
+func (p *prettyPrinter) print(r io.Reader) error {
+	dec := hclog.NewDecoder(r, nil)
+
+	for {
+		e, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if e.Format != hclog.OutputJSON || e.Level == hclog.NoLevel {
+			if _, err := fmt.Fprintln(p.out, e.Raw); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if p.relative && !e.Time.IsZero() {
+			if p.start.IsZero() {
+				p.start = e.Time
+			}
+
+			fmt.Fprintf(p.out, "%+10.3fs ", e.Time.Sub(p.start).Seconds())
+		}
+
+		e.Args = groupArgs(e.Args)
+		p.entries.write(e)
+	}
+}
+
+// groupArgs returns args with the JSON objects among their values as
+// groups, so that they're written with dotted keys.
+func groupArgs(args []any) []any {
+	grouped := make([]any, 0, len(args))
+
+	for i := 0; i+1 < len(args); i += 2 {
+		key, _ := args[i].(string)
+
+		if obj, ok := args[i+1].(map[string]any); ok {
+			grouped = append(grouped, hclog.Group(key, objectArgs(obj)...))
+			continue
+		}
+
+		grouped = append(grouped, args[i], args[i+1])
+	}
+
+	return grouped
+}
+
+// objectArgs returns the members of obj as args, sorted by key.
+func objectArgs(obj map[string]any) []any {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	args := make([]any, 0, 2*len(keys))
+	for _, k := range keys {
+		args = append(args, k, obj[k])
+	}
+
+	return groupArgs(args)
+}
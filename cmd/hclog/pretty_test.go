@@ -0,0 +1,139 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logBoth writes the same entries to a JSON and a plain logger, returning
+// the output of each.
+func logBoth(t *testing.T, opts hclog.LoggerOptions, fn func(hclog.Logger)) (string, string) {
+	t.Helper()
+
+	var js, plain bytes.Buffer
+
+	now := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+
+	opts.Name = "app"
+	opts.Level = hclog.Trace
+	opts.IncludeLocation = true
+	opts.TimeFn = func() time.Time { return now }
+
+	jsOpts := opts
+	jsOpts.Output = &js
+	jsOpts.JSONFormat = true
+	jsOpts.Color = hclog.ColorOff
+	fn(hclog.New(&jsOpts))
+
+	opts.Output = &plain
+	fn(hclog.New(&opts))
+
+	return js.String(), plain.String()
+}
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return stdout.String(), stderr.String(), code
+}
+
+func TestPretty(t *testing.T) {
+	entries := func(logger hclog.Logger) {
+		logger.Info("starting", "port", 8500, hclog.Group("req", "id", 1, "path", "/a b"))
+		logger.Named("raft").Warn("retrying", "body", "one\ntwo", "attempt", 2)
+		logger.Error("failed", "error", errors.New("boom"))
+		logger.ResetNamed("").Debug("unnamed")
+	}
+
+	t.Run("renders JSON as plain", func(t *testing.T) {
+		js, plain := logBoth(t, hclog.LoggerOptions{TimeFormat: hclog.TimeFormat}, entries)
+
+		stdout, stderr, code := runCommand(t, js, "pretty", "-color", "never")
+		assert.Equal(t, 0, code)
+		assert.Empty(t, stderr)
+		assert.Equal(t, plain, stdout)
+	})
+
+	t.Run("colors the output", func(t *testing.T) {
+		js, plain := logBoth(t, hclog.LoggerOptions{
+			Color:                hclog.ForceColor,
+			ColorHeaderAndFields: true,
+		}, entries)
+
+		stdout, _, code := runCommand(t, js, "pretty", "-color", "always")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, plain, stdout)
+	})
+
+	t.Run("colors only the header", func(t *testing.T) {
+		js, plain := logBoth(t, hclog.LoggerOptions{
+			Color:           hclog.ForceColor,
+			ColorHeaderOnly: true,
+		}, entries)
+
+		stdout, _, code := runCommand(t, js, "pretty", "-color", "always", "-header-only")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, plain, stdout)
+	})
+
+	t.Run("writes relative times", func(t *testing.T) {
+		in := `{"@timestamp":"2024-05-06T07:08:09.000000Z","@level":"info","@message":"first"}
+{"@timestamp":"2024-05-06T07:08:10.250000Z","@level":"warn","@message":"second","a":1}
+`
+
+		stdout, _, code := runCommand(t, in, "pretty", "-color", "never", "-relative")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "    +0.000s [INFO]  first\n    +1.250s [WARN]  second: a=1\n", stdout)
+	})
+
+	t.Run("passes other lines through", func(t *testing.T) {
+		in := "starting up\n" +
+			`{"@level":"info","@message":"hello"}` + "\n" +
+			"{not json}\n" +
+			"2024-05-06T07:08:09.000Z [INFO]  already plain: a=1\n"
+
+		stdout, _, code := runCommand(t, in, "pretty", "-color", "never")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "starting up\n"+
+			"[INFO]  hello\n"+
+			"{not json}\n"+
+			"2024-05-06T07:08:09.000Z [INFO]  already plain: a=1\n", stdout)
+	})
+
+	t.Run("reads files", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		require.NoError(t, os.WriteFile(path, []byte(`{"@level":"info","@message":"from file"}`+"\n"), 0o600))
+
+		stdout, stderr, code := runCommand(t, `{"@level":"info","@message":"from stdin"}`,
+			"pretty", "-color", "never", path, "-", filepath.Join(dir, "missing.log"))
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "[INFO]  from file\n[INFO]  from stdin\n", stdout)
+		assert.Contains(t, stderr, "missing.log")
+	})
+
+	t.Run("rejects bad flags", func(t *testing.T) {
+		_, stderr, code := runCommand(t, "", "pretty", "-color", "sometimes")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, `invalid -color "sometimes"`)
+
+		_, stderr, code = runCommand(t, "", "prettify")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, `unknown command "prettify"`)
+	})
+}
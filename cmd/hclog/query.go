@@ -0,0 +1,155 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// runQuery writes the entries of its inputs which match an expression, in
+// their original format, as JSON, or as selected columns. Entries are
+// matched whole, including the continuation lines of multi-line values and
+// stacktraces.
+func runQuery(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), `Usage: hclog query [flags] expression [file ...]
+
+The expression joins comparisons such as level>=warn, module~"^raft" or
+duration_ms>500 with &&, || and !. The left hand side of a comparison is
+level, module, msg, caller, time, or the key of an arg, with dotted keys for
+the members of JSON objects. The operators are ==, !=, <, <=, >, >=, ~ and !~
+for regular expressions, ^= for a prefix and *= for a substring. Regular
+expressions match anywhere in the value, so anchor them with ^ and $ to match
+all of it. A key on its own matches entries which have that arg.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+
+	var (
+		output  = fs.String("output", "original", "the format of matching entries: original or json")
+		columns = fs.String("columns", "", "write these comma separated columns of matching entries, separated by tabs")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	match, err := parseFilter(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "hclog query: invalid expression: %v\n", err)
+		return 2
+	}
+
+	var write func(e *hclog.Entry) error
+
+	switch {
+	case *columns != "":
+		cols := strings.Split(*columns, ",")
+		write = func(e *hclog.Entry) error {
+			_, err := fmt.Fprintln(stdout, strings.Join(columnValues(e, cols), "\t"))
+			return err
+		}
+	case *output == "original":
+		write = func(e *hclog.Entry) error {
+			_, err := fmt.Fprintln(stdout, e.Raw)
+			return err
+		}
+	case *output == "json":
+		entries := newEntryWriter(&hclog.LoggerOptions{
+			Output:     stdout,
+			JSONFormat: true,
+		})
+
+		write = func(e *hclog.Entry) error {
+			entries.write(e)
+			return nil
+		}
+	default:
+		fmt.Fprintf(stderr, "hclog query: invalid -output %q\n", *output)
+		return 2
+	}
+
+	ok := eachInput("query", fs.Args()[1:], stdin, stderr, func(r io.Reader) error {
+		dec := hclog.NewDecoder(r, nil)
+
+		for {
+			e, err := dec.Decode()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			if !match(e) {
+				continue
+			}
+
+			if err := write(e); err != nil {
+				return err
+			}
+		}
+	})
+
+	if !ok {
+		return 1
+	}
+
+	return 0
+}
+
+// columnValues returns the values of the named columns of e. Columns are
+// named as in expressions, and values which contain tabs or newlines are
+// quoted.
+func columnValues(e *hclog.Entry, cols []string) []string {
+	vals := make([]string, len(cols))
+
+	for i, col := range cols {
+		var s string
+
+		switch col {
+		case "time":
+			if !e.Time.IsZero() {
+				s = e.Time.Format(time.RFC3339Nano)
+			}
+		case "level":
+			if e.Level != hclog.NoLevel {
+				s = e.Level.String()
+			}
+		case "module", "name":
+			s = e.Name
+		case "msg", "message":
+			s = e.Message
+		case "caller":
+			s = e.Caller
+		default:
+			if v, ok := lookupArg(e.Args, col); ok {
+				s = valueText(v)
+			}
+		}
+
+		if strings.ContainsAny(s, "\t\n") {
+			s = strconv.Quote(s)
+		}
+
+		vals[i] = s
+	}
+
+	return vals
+}
@@ -0,0 +1,87 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+
+	var plain, js bytes.Buffer
+
+	newLogger := func(w *bytes.Buffer, json bool) hclog.Logger {
+		return hclog.New(&hclog.LoggerOptions{
+			Output:     w,
+			Level:      hclog.Trace,
+			JSONFormat: json,
+			TimeFn:     func() time.Time { return now },
+		})
+	}
+
+	raft := newLogger(&plain, false).Named("raft")
+	raft.Info("elected", "term", 3)
+	raft.Warn("slow append", "duration_ms", 750, "body", "one\ntwo")
+	plain.WriteString("panic: oops\n")
+	newLogger(&plain, false).Named("http").Error("failed", "duration_ms", 900)
+
+	newLogger(&js, true).Named("raft.snapshot").Warn("slow snapshot", "duration_ms", 1200, hclog.Group("req", "id", 7))
+
+	input := plain.String() + js.String()
+
+	lines := strings.SplitAfter(plain.String(), "\n")
+
+	t.Run("writes matching entries whole", func(t *testing.T) {
+		stdout, stderr, code := runCommand(t, input, "query", `level>=warn && module~"raft.*" && duration_ms>500`)
+		assert.Equal(t, 0, code)
+		assert.Empty(t, stderr)
+		assert.Equal(t, strings.Join(lines[1:6], "")+js.String(), stdout)
+	})
+
+	t.Run("matches other lines by message", func(t *testing.T) {
+		stdout, _, code := runCommand(t, input, "query", `msg~"^panic"`)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "panic: oops\n", stdout)
+	})
+
+	t.Run("writes JSON", func(t *testing.T) {
+		stdout, _, code := runCommand(t, input, "query", "-output", "json", "level>=error || req.id==7")
+		assert.Equal(t, 0, code)
+		assert.Equal(t,
+			`{"@timestamp":"2024-05-06T07:08:09.123000Z","@level":"error","@message":"failed","@module":"http","duration_ms":"900"}`+"\n"+
+				js.String(),
+			stdout)
+	})
+
+	t.Run("writes columns", func(t *testing.T) {
+		stdout, _, code := runCommand(t, input, "query", "-columns", "time,level,module,msg,duration_ms,body,req.id", "duration_ms")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, ""+
+			"2024-05-06T07:08:09.123Z\twarn\traft\tslow append\t750\t\"one\\ntwo\"\t\n"+
+			"2024-05-06T07:08:09.123Z\terror\thttp\tfailed\t900\t\t\n"+
+			"2024-05-06T07:08:09.123Z\twarn\traft.snapshot\tslow snapshot\t1200\t\t7\n",
+			stdout)
+	})
+
+	t.Run("rejects bad arguments", func(t *testing.T) {
+		_, stderr, code := runCommand(t, "", "query")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "Usage: hclog query")
+
+		_, stderr, code = runCommand(t, "", "query", "level>=")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "invalid expression")
+
+		_, stderr, code = runCommand(t, "", "query", "-output", "xml", "")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, `invalid -output "xml"`)
+	})
+}
@@ -0,0 +1,694 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is an entry read from the output of a Logger by a Decoder.
+type Entry struct {
+	// Time is zero if the entry has no timestamp.
+	Time time.Time
+
+	// Level is NoLevel for a line which isn't part of an entry.
+	Level   Level
+	Name    string
+	Message string
+
+	// Caller is the location of the logging call, as file:line, if the
+	// logger had IncludeLocation set.
+	Caller string
+
+	// Args are the key/value pairs of the entry, in the order written. The
+	// values of an entry in the plain format are strings, unquoted and
+	// unescaped. Those of an entry in the JSON format are as decoded by
+	// encoding/json, with numbers as json.Number.
+	Args []any
+
+	Stacktrace CapturedStacktrace
+
+	// Format is OutputPlain or OutputJSON.
+	Format OutputFormat
+
+	// Raw is the text the entry was read from, without the final newline.
+	Raw string
+}
+
+// DecoderOptions configures a Decoder. They should match the LoggerOptions
+// of the logger which wrote the output.
+type DecoderOptions struct {
+	// TimeFormat is the format of the timestamps of the plain format, and
+	// defaults to TimeFormat.
+	TimeFormat string
+
+	// JSONFields are the names of the envelope fields of the JSON format,
+	// with empty names defaulting as in LoggerOptions.JSONFields. Unlike
+	// there, a field can't be dropped.
+	JSONFields *JSONFields
+}
+
+// Decoder reads entries written by a Logger in the plain or JSON format,
+// which may be mixed in the same stream. Colors are ignored.
+//
+// The plain format isn't always unambiguous. A message containing ": " is
+// read as a name followed by a message if the part before it has no spaces,
+// and a message followed by text resembling key=value pairs is read as
+// args. Lines following an entry which look like the lines of a stacktrace
+// are read as its stacktrace.
+type Decoder struct {
+	r          *bufio.Reader
+	timeFormat string
+	jsonFields JSONFields
+
+	// peeked holds the lines read ahead of the current one.
+	peeked []string
+	err    error
+}
+
+// NewDecoder returns a Decoder which reads entries from r.
+func NewDecoder(r io.Reader, opts *DecoderOptions) *Decoder {
+	if opts == nil {
+		opts = &DecoderOptions{}
+	}
+
+	d := &Decoder{
+		r:          bufio.NewReader(r),
+		timeFormat: opts.TimeFormat,
+	}
+
+	if d.timeFormat == "" {
+		d.timeFormat = TimeFormat
+	}
+
+	if opts.JSONFields != nil {
+		d.jsonFields = *opts.JSONFields
+	}
+
+	defaultString(&d.jsonFields.Timestamp, "@timestamp")
+	defaultString(&d.jsonFields.Level, "@level")
+	defaultString(&d.jsonFields.Message, "@message")
+	defaultString(&d.jsonFields.Module, "@module")
+	defaultString(&d.jsonFields.Caller, "@caller")
+	defaultString(&d.jsonFields.Stacktrace, "stacktrace")
+
+	return d
+}
+
+func defaultString(s *string, def string) {
+	if *s == "" {
+		*s = def
+	}
+}
+
+// Decode returns the next entry, or io.EOF once there are no more. A line
+// which isn't part of an entry is returned as an Entry with NoLevel and the
+// line as its message.
+func (d *Decoder) Decode() (*Entry, error) {
+	line, ok := d.next()
+	if !ok {
+		return nil, d.err
+	}
+
+	text := stripANSI(line)
+
+	if e, ok := d.parseJSON(text); ok {
+		e.Raw = line
+		return e, nil
+	}
+
+	e, rest, ok := d.parsePlainHeader(text)
+	if !ok {
+		return &Entry{Level: NoLevel, Message: line, Raw: line}, nil
+	}
+
+	raw := []string{line}
+
+	head, args, hasArgs := splitPlainArgs(rest, d.blockFollows())
+	e.Name, e.Message = splitPlainName(head)
+	e.Args = args
+
+	// Values with multiple lines follow the first line, each followed by
+	// any args after it.
+	for hasArgs && d.blockFollows() {
+		line, _ := d.next()
+		raw = append(raw, line)
+
+		key := strings.TrimSuffix(strings.TrimPrefix(stripANSI(line), "  "), "=")
+
+		var lines []string
+		for {
+			line, ok := d.peek(0)
+			if !ok {
+				break
+			}
+
+			text, ok := strings.CutPrefix(stripANSI(line), "  |")
+			if !ok {
+				break
+			}
+
+			d.next()
+			raw = append(raw, line)
+
+			text = strings.TrimPrefix(text, " ")
+			lines = append(lines, unescapeOutput(text, false))
+		}
+
+		e.Args = append(e.Args, key, strings.Join(lines, "\n"))
+
+		if line, ok := d.peek(0); ok {
+			if text, ok := strings.CutPrefix(stripANSI(line), "  "); ok {
+				if pairs, ok := parsePlainPairs(text); ok {
+					d.next()
+					raw = append(raw, line)
+					e.Args = append(e.Args, pairs...)
+				}
+			}
+		}
+	}
+
+	var stack []string
+	for {
+		line, ok := d.peek(0)
+		if !ok || d.entryStart(line) {
+			break
+		}
+
+		// A stacktrace is made of pairs of lines, the name of a function
+		// followed by its file and line indented with a tab.
+		if !strings.HasPrefix(line, "\t") {
+			if next, ok := d.peek(1); !ok || !strings.HasPrefix(next, "\t") {
+				break
+			}
+		}
+
+		d.next()
+		raw = append(raw, line)
+		stack = append(stack, line)
+	}
+
+	e.Stacktrace = CapturedStacktrace(strings.Join(stack, "\n"))
+	e.Raw = strings.Join(raw, "\n")
+
+	return e, nil
+}
+
+// peek returns the line n lines after the current one without consuming it.
+func (d *Decoder) peek(n int) (string, bool) {
+	for len(d.peeked) <= n {
+		if d.err != nil {
+			return "", false
+		}
+
+		line, err := d.r.ReadString('\n')
+		if err != nil {
+			d.err = err
+			if line == "" {
+				return "", false
+			}
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		line = strings.TrimSuffix(line, "\r")
+		d.peeked = append(d.peeked, line)
+	}
+
+	return d.peeked[n], true
+}
+
+// next consumes and returns the current line.
+func (d *Decoder) next() (string, bool) {
+	line, ok := d.peek(0)
+	if ok {
+		d.peeked = d.peeked[1:]
+	}
+
+	return line, ok
+}
+
+// entryStart reports whether line is the first line of an entry.
+func (d *Decoder) entryStart(line string) bool {
+	text := stripANSI(line)
+
+	if strings.HasPrefix(text, "{") {
+		return true
+	}
+
+	_, _, ok := d.parsePlainHeader(text)
+	return ok
+}
+
+var plainBlockStart = regexp.MustCompile(`^  [^ =]+=$`)
+
+// blockFollows reports whether the next line starts a value with multiple
+// lines.
+func (d *Decoder) blockFollows() bool {
+	line, ok := d.peek(0)
+	return ok && plainBlockStart.MatchString(stripANSI(line))
+}
+
+var plainLevels = map[string]Level{
+	"TRACE": Trace,
+	"DEBUG": Debug,
+	"INFO":  Info,
+	"WARN":  Warn,
+	"ERROR": Error,
+	"?????": NoLevel,
+}
+
+var plainCaller = regexp.MustCompile(`^\S+\.go:\d+:$`)
+
+// parsePlainHeader parses the time, level and caller at the start of the
+// first line of an entry in the plain format, and returns the rest of the
+// line.
+func (d *Decoder) parsePlainHeader(line string) (*Entry, string, bool) {
+	for i := 0; i < len(line); i++ {
+		if line[i] != '[' {
+			continue
+		}
+
+		end := strings.IndexByte(line[i:], ']')
+		if end < 0 {
+			break
+		}
+
+		name := line[i+1 : i+end]
+		level, ok := plainLevels[name]
+		if !ok {
+			continue
+		}
+
+		t, ok := d.parsePlainTime(line[:i])
+		if !ok {
+			continue
+		}
+
+		e := &Entry{
+			Time:   t,
+			Level:  level,
+			Format: OutputPlain,
+		}
+
+		rest := line[i+end+1:]
+
+		// The brackets of shorter level names are padded to the same width.
+		if len(name) == 4 {
+			rest = strings.TrimPrefix(rest, " ")
+		}
+		rest = strings.TrimPrefix(rest, " ")
+
+		if tok, after, ok := strings.Cut(rest, " "); ok && plainCaller.MatchString(tok) {
+			e.Caller = strings.TrimSuffix(tok, ":")
+			rest = after
+		}
+
+		return e, rest, true
+	}
+
+	return nil, "", false
+}
+
+// parsePlainTime parses the text before the level of a plain entry, which
+// is either empty or a timestamp followed by a space.
+func (d *Decoder) parsePlainTime(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, true
+	}
+
+	s, ok := strings.CutSuffix(s, " ")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(d.timeFormat, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// splitPlainArgs splits the rest of the first line of a plain entry at the
+// first colon followed by valid key/value pairs. A colon at the end of the
+// line, which is followed by values with multiple lines, is accepted if
+// open is set.
+func splitPlainArgs(rest string, open bool) (head string, args []any, hasArgs bool) {
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != ':' {
+			continue
+		}
+
+		tail := rest[i+1:]
+		if tail == "" {
+			if open {
+				return rest[:i], nil, true
+			}
+
+			break
+		}
+
+		if pairs, ok := parsePlainPairs(tail); ok && len(pairs) > 0 {
+			return rest[:i], pairs, true
+		}
+	}
+
+	return rest, nil, false
+}
+
+// splitPlainName splits the name of the logger from the message.
+func splitPlainName(head string) (name, msg string) {
+	if name, msg, ok := strings.Cut(head, ": "); ok && !strings.Contains(name, " ") {
+		return name, msg
+	}
+
+	return "", head
+}
+
+// parsePlainPairs parses a sequence of key/value pairs, each preceded by a
+// space, as written by writePlainArgs.
+func parsePlainPairs(s string) ([]any, bool) {
+	var pairs []any
+
+	for s != "" {
+		var ok bool
+		if s, ok = strings.CutPrefix(s, " "); !ok {
+			return nil, false
+		}
+
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || strings.ContainsAny(s[:eq], ` "`) {
+			return nil, false
+		}
+
+		key := s[:eq]
+		s = s[eq+1:]
+
+		val, n, ok := parsePlainValue(s)
+		if !ok {
+			return nil, false
+		}
+
+		s = s[n:]
+		if s != "" && s[0] != ' ' {
+			return nil, false
+		}
+
+		pairs = append(pairs, key, val)
+	}
+
+	return pairs, true
+}
+
+// parsePlainValue parses the value at the start of s, returning it and its
+// length in s. A value is either quoted, a slice in brackets, or runs until
+// the next space.
+func parsePlainValue(s string) (string, int, bool) {
+	if s == "" {
+		return "", 0, false
+	}
+
+	switch s[0] {
+	case '"':
+		end := quotedEnd(s)
+		if end < 0 {
+			return "", 0, false
+		}
+
+		return unescapeOutput(s[1:end-1], true), end, true
+	case '[':
+		depth := 0
+		for i := 0; i < len(s); i++ {
+			switch s[i] {
+			case '"':
+				end := quotedEnd(s[i:])
+				if end < 0 {
+					return "", 0, false
+				}
+				i += end - 1
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					return s[:i+1], i + 1, true
+				}
+			}
+		}
+
+		return "", 0, false
+	default:
+		end := strings.IndexByte(s, ' ')
+		if end < 0 {
+			end = len(s)
+		}
+
+		return s[:end], end, true
+	}
+}
+
+// quotedEnd returns the length of the quoted string at the start of s, or -1
+// if it's not terminated.
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// unescapeOutput reverses writeEscapedForOutput, and the escaping of Quote
+// values. Backslashes which don't start an escape are left as is.
+func unescapeOutput(s string, quoted bool) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		var r rune
+		width := 0
+
+		switch c := s[i+1]; c {
+		case 'a':
+			r = '\a'
+		case 'b':
+			r = '\b'
+		case 'f':
+			r = '\f'
+		case 'n':
+			r = '\n'
+		case 'r':
+			r = '\r'
+		case 't':
+			r = '\t'
+		case 'v':
+			r = '\v'
+		case '\\':
+			r = '\\'
+		case '"':
+			if !quoted {
+				sb.WriteByte(s[i])
+				continue
+			}
+			r = '"'
+		case 'x':
+			width = 2
+		case 'u':
+			width = 4
+		case 'U':
+			width = 8
+		default:
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		if width > 0 {
+			if i+2+width > len(s) {
+				sb.WriteByte(s[i])
+				continue
+			}
+
+			n, err := strconv.ParseUint(s[i+2:i+2+width], 16, 32)
+			if err != nil {
+				sb.WriteByte(s[i])
+				continue
+			}
+
+			r = rune(n)
+			i += width
+		}
+
+		if width == 2 {
+			sb.WriteByte(byte(r))
+		} else {
+			sb.WriteRune(r)
+		}
+
+		i++
+	}
+
+	return sb.String()
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// stripANSI removes ANSI escape sequences, as written for colors.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// parseJSON parses an entry in the JSON format.
+func (d *Decoder) parseJSON(line string) (*Entry, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+
+	val, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, false
+	}
+
+	pairs, ok := val.(jsonPairs)
+	if !ok {
+		return nil, false
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false
+	}
+
+	e := &Entry{Format: OutputJSON}
+	f := d.jsonFields
+
+	for i := 0; i < len(pairs); i += 2 {
+		key, val := pairs[i].(string), pairs[i+1]
+		s, isString := val.(string)
+
+		switch {
+		case key == f.Timestamp && isString:
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, false
+			}
+			e.Time = t
+		case key == f.Level && isString:
+			e.Level = LevelFromString(s)
+		case key == f.Message && isString:
+			e.Message = s
+		case key == f.Module && isString:
+			e.Name = s
+		case key == f.Caller && isString:
+			e.Caller = s
+		case key == f.Stacktrace && isString:
+			e.Stacktrace = CapturedStacktrace(s)
+		case key == f.Args && f.Args != "":
+			if args, ok := val.(jsonPairs); ok {
+				for j := 0; j < len(args); j += 2 {
+					e.Args = append(e.Args, args[j], jsonObjects(args[j+1]))
+				}
+			}
+		default:
+			e.Args = append(e.Args, key, jsonObjects(val))
+		}
+	}
+
+	if e.Level == NoLevel {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// jsonPairs holds the members of a JSON object in order, as key/value pairs.
+type jsonPairs []any
+
+// decodeJSONValue decodes the next value from dec, with objects as
+// jsonPairs.
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		pairs := jsonPairs{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			pairs = append(pairs, key, val)
+		}
+
+		_, err = dec.Token()
+		return pairs, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			val, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, val)
+		}
+
+		_, err = dec.Token()
+		return list, err
+	default:
+		return tok, nil
+	}
+}
+
+// jsonObjects returns val with the objects within it as maps, as
+// encoding/json would decode them.
+func jsonObjects(val any) any {
+	switch v := val.(type) {
+	case jsonPairs:
+		m := make(map[string]any, len(v)/2)
+		for i := 0; i < len(v); i += 2 {
+			m[v[i].(string)] = jsonObjects(v[i+1])
+		}
+		return m
+	case []any:
+		for i := range v {
+			v[i] = jsonObjects(v[i])
+		}
+		return v
+	default:
+		return val
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeAll returns all the entries read from s.
+func decodeAll(t *testing.T, s string, opts *DecoderOptions) []*Entry {
+	t.Helper()
+
+	dec := NewDecoder(strings.NewReader(s), opts)
+
+	var entries []*Entry
+	for {
+		e, err := dec.Decode()
+		if err == io.EOF {
+			return entries
+		}
+		require.NoError(t, err)
+
+		entries = append(entries, e)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.FixedZone("", -7*3600))
+
+	cases := []struct {
+		name  string
+		msg   string
+		args  []any
+		plain []any
+		json  []any
+	}{
+		{
+			name:  "simple values",
+			msg:   "hello",
+			args:  []any{"a", "b", "n", 1, "ok", true},
+			plain: []any{"a", "b", "n", "1", "ok", "true"},
+			json:  []any{"a", "b", "n", json.Number("1"), "ok", true},
+		},
+		{
+			name:  "quoted values",
+			msg:   "with: colon",
+			args:  []any{"s", "hello world", "q", `say "hi"`, "tab", "a\tb", "e", "", "u", "héllo", "ctl", "\x01", "url", "http://a:1/x"},
+			plain: []any{"s", "hello world", "q", `say "hi"`, "tab", "a\tb", "e", "", "u", "héllo", "ctl", "\x01", "url", "http://a:1/x"},
+			json:  []any{"s", "hello world", "q", `say "hi"`, "tab", "a\tb", "e", "", "u", "héllo", "ctl", "\x01", "url", "http://a:1/x"},
+		},
+		{
+			name:  "multiple lines",
+			msg:   "dump",
+			args:  []any{"first", 1, "body", "line1\n\tline2", "after", "x y", "last", "a\nb"},
+			plain: []any{"first", "1", "body", "line1\n\tline2", "after", "x y", "last", "a\nb"},
+			json:  []any{"first", json.Number("1"), "body", "line1\n\tline2", "after", "x y", "last", "a\nb"},
+		},
+		{
+			name:  "only multiple lines",
+			msg:   "dump",
+			args:  []any{"body", "one\ntwo"},
+			plain: []any{"body", "one\ntwo"},
+			json:  []any{"body", "one\ntwo"},
+		},
+		{
+			name:  "slices, quotes and groups",
+			msg:   "collections",
+			args:  []any{"list", []string{"a b", "c"}, "quote", Quote("x y"), Group("req", "id", 1, "path", "/")},
+			plain: []any{"list", `["a b", "c"]`, "quote", "x y", "req.id", "1", "req.path", "/"},
+			json:  []any{"list", []any{"a b", "c"}, "quote", "x y", "req", map[string]any{"id": json.Number("1"), "path": "/"}},
+		},
+		{
+			name: "no args",
+			msg:  "usage: none",
+		},
+	}
+
+	type loggerCase struct {
+		name string
+		opts LoggerOptions
+	}
+
+	loggers := []loggerCase{
+		{name: "plain", opts: LoggerOptions{}},
+		{name: "plain with time and location", opts: LoggerOptions{TimeFn: func() time.Time { return now }, IncludeLocation: true}},
+		{name: "plain with color", opts: LoggerOptions{Color: ForceColor, ColorHeaderAndFields: true}},
+		{name: "json", opts: LoggerOptions{JSONFormat: true, TimeFn: func() time.Time { return now }, IncludeLocation: true}},
+	}
+
+	for _, lc := range loggers {
+		for _, c := range cases {
+			for _, name := range []string{"", "app.http"} {
+				t.Run(lc.name+"/"+c.name+"/"+name, func(t *testing.T) {
+					var buf bytes.Buffer
+
+					opts := lc.opts
+					opts.Output = &buf
+					opts.Name = name
+					opts.DisableTime = opts.TimeFn == nil
+
+					New(&opts).Warn(c.msg, c.args...)
+
+					entries := decodeAll(t, buf.String(), nil)
+					require.Len(t, entries, 1, buf.String())
+					e := entries[0]
+
+					wantName, wantMsg := name, c.msg
+					if name == "" && !opts.JSONFormat {
+						// Without a name, a message such as "usage: none" is
+						// read as a name and message.
+						wantName, wantMsg = splitPlainName(c.msg)
+					}
+
+					assert.Equal(t, Warn, e.Level)
+					assert.Equal(t, wantName, e.Name)
+					assert.Equal(t, wantMsg, e.Message)
+					assert.Equal(t, strings.TrimSuffix(buf.String(), "\n"), e.Raw)
+
+					if opts.TimeFn != nil {
+						assert.True(t, now.Equal(e.Time), e.Time)
+					} else {
+						assert.True(t, e.Time.IsZero())
+					}
+
+					if opts.IncludeLocation {
+						assert.Regexp(t, `^.*decoder_test\.go:\d+$`, e.Caller)
+					} else {
+						assert.Empty(t, e.Caller)
+					}
+
+					if opts.JSONFormat {
+						assert.Equal(t, OutputJSON, e.Format)
+						assert.Equal(t, c.json, e.Args)
+					} else {
+						assert.Equal(t, OutputPlain, e.Format)
+						assert.Equal(t, c.plain, e.Args)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestDecoderStream(t *testing.T) {
+	t.Run("reads mixed formats and other lines", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		plain := New(&LoggerOptions{Output: &buf, DisableTime: true, Level: Debug})
+		js := New(&LoggerOptions{Output: &buf, DisableTime: true, JSONFormat: true})
+
+		buf.WriteString("starting up\n")
+		plain.Info("first", "a", 1)
+		js.Error("second", "b", 2)
+		buf.WriteString("panic: oops\n")
+		plain.Debug("third", "body", "x\ny")
+		buf.WriteString("{not json}\n")
+
+		entries := decodeAll(t, buf.String(), nil)
+		require.Len(t, entries, 6)
+
+		assert.Equal(t, &Entry{Level: NoLevel, Message: "starting up", Raw: "starting up"}, entries[0])
+		assert.Equal(t, "first", entries[1].Message)
+		assert.Equal(t, OutputJSON, entries[2].Format)
+		assert.Equal(t, Error, entries[2].Level)
+		assert.Equal(t, []any{"b", json.Number("2")}, entries[2].Args)
+		assert.Equal(t, NoLevel, entries[3].Level)
+		assert.Equal(t, "panic: oops", entries[3].Message)
+		assert.Equal(t, []any{"body", "x\ny"}, entries[4].Args)
+		assert.Equal(t, NoLevel, entries[5].Level)
+	})
+
+	t.Run("reads stacktraces", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{Output: &buf, DisableTime: true})
+
+		stack := Stacktrace()
+		logger.Error("failed", "error", errors.New("boom"), stack)
+		logger.Info("next")
+
+		entries := decodeAll(t, buf.String(), nil)
+		require.Len(t, entries, 2, buf.String())
+
+		assert.Equal(t, []any{"error", "boom"}, entries[0].Args)
+		assert.Equal(t, strings.TrimSuffix(string(stack), "\n"), string(entries[0].Stacktrace))
+		assert.Equal(t, "next", entries[1].Message)
+	})
+
+	t.Run("uses the options of the logger", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+		fields := &JSONFields{Level: "severity", Message: "msg", Args: "fields"}
+
+		New(&LoggerOptions{
+			Output:     &buf,
+			TimeFn:     func() time.Time { return now },
+			TimeFormat: time.DateTime,
+		}).Info("plain", "a", 1)
+
+		New(&LoggerOptions{
+			Output:     &buf,
+			JSONFormat: true,
+			JSONFields: fields,
+			TimeFn:     func() time.Time { return now },
+		}).Info("json", "b", 2, "c", 3)
+
+		entries := decodeAll(t, buf.String(), &DecoderOptions{
+			TimeFormat: time.DateTime,
+			JSONFields: fields,
+		})
+		require.Len(t, entries, 2, buf.String())
+
+		assert.Equal(t, now, entries[0].Time)
+		assert.Equal(t, "plain", entries[0].Message)
+
+		assert.True(t, now.Equal(entries[1].Time))
+		assert.Equal(t, Info, entries[1].Level)
+		assert.Equal(t, "json", entries[1].Message)
+		assert.Equal(t, []any{"b", json.Number("2"), "c", json.Number("3")}, entries[1].Args)
+	})
+}
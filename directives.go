@@ -0,0 +1,186 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// LevelDirectives assigns levels to loggers based on their name, so that a
+// subsystem can be made more or less verbose than the rest of the logger tree.
+// Directives are written as a comma separated list of name=level pairs, for
+// example:
+//
+//	raft=debug,http.server=warn,*=info
+//
+// A directive applies to the named logger and to every logger beneath it, so
+// "http" also covers "http.server" and "http.client", and the most specific
+// matching directive wins. The name "*" matches any logger not covered by
+// another directive, and a level given without a name is shorthand for "*".
+//
+// Names are matched relative to the root logger, so with a root logger named
+// "app", the directive "raft" applies to the logger returned by
+// Named("raft"), whose full name is "app.raft". Loggers created with
+// ResetNamed are matched using their full name.
+//
+// A logger covered by a directive uses the level from the directive rather
+// than the one given via LoggerOptions.Level or SetLevel. Loggers not covered
+// by any directive behave as though no directives were set.
+//
+// LevelDirectives is safe for concurrent use, and Set can be called at any
+// time to change the levels of every logger using it.
+type LevelDirectives struct {
+	rules atomic.Pointer[levelRules]
+
+	// gen is incremented each time the rules change, allowing loggers to
+	// cache the result of matching their name.
+	gen atomic.Uint64
+}
+
+// levelRules is an immutable set of parsed directives.
+type levelRules struct {
+	spec     string
+	names    map[string]Level
+	fallback Level
+}
+
+// ParseLevelDirectives parses a directive string as described on
+// LevelDirectives. An empty string is valid and sets no directives.
+func ParseLevelDirectives(spec string) (*LevelDirectives, error) {
+	d := new(LevelDirectives)
+	if err := d.Set(spec); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Set replaces the current directives with those parsed from spec. If spec is
+// invalid an error is returned and the current directives are left in place.
+func (d *LevelDirectives) Set(spec string) error {
+	rules, err := parseLevelRules(spec)
+	if err != nil {
+		return err
+	}
+
+	d.rules.Store(rules)
+	d.gen.Add(1)
+
+	return nil
+}
+
+// String returns the directives in their canonical form, sorted by name with
+// the "*" directive last.
+func (d *LevelDirectives) String() string {
+	rules := d.rules.Load()
+	if rules == nil {
+		return ""
+	}
+
+	return rules.spec
+}
+
+// LevelFor returns the level the directives assign to a logger with the given
+// name, relative to the root logger, or NoLevel if no directive applies.
+func (d *LevelDirectives) LevelFor(name string) Level {
+	rules := d.rules.Load()
+	if rules == nil {
+		return NoLevel
+	}
+
+	for {
+		if level, ok := rules.names[name]; ok {
+			return level
+		}
+
+		idx := strings.LastIndexByte(name, '.')
+		if idx == -1 {
+			return rules.fallback
+		}
+
+		name = name[:idx]
+	}
+}
+
+// cachedLevel returns the level for the logger name, relative to root. The
+// result is stored in cache along with the generation of the rules it was
+// computed from so that the name only needs to be matched again once the
+// directives change.
+func (d *LevelDirectives) cachedLevel(cache *atomic.Uint64, root, name string) Level {
+	gen := d.gen.Load()
+
+	// The level occupies the low byte, the generation the remainder. The
+	// generation starts at 1 once rules are set, so an empty cache never
+	// matches.
+	if c := cache.Load(); c>>8 == gen {
+		return Level(c & 0xff)
+	}
+
+	switch {
+	case root == "":
+	case name == root:
+		name = ""
+	case strings.HasPrefix(name, root+"."):
+		name = name[len(root)+1:]
+	}
+
+	level := d.LevelFor(name)
+	cache.Store(gen<<8 | uint64(level))
+
+	return level
+}
+
+func parseLevelRules(spec string) (*levelRules, error) {
+	rules := &levelRules{
+		names: make(map[string]Level),
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, levelStr, found := strings.Cut(part, "=")
+		if !found {
+			name, levelStr = "*", name
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("missing logger name in level directive %q", part)
+		}
+
+		level := LevelFromString(levelStr)
+		if level == NoLevel {
+			return nil, fmt.Errorf("invalid level %q in level directive %q", strings.TrimSpace(levelStr), part)
+		}
+
+		if name == "*" {
+			rules.fallback = level
+		} else {
+			rules.names[name] = level
+		}
+	}
+
+	names := make([]string, 0, len(rules.names))
+	for name := range rules.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		parts = append(parts, name+"="+rules.names[name].String())
+	}
+	if rules.fallback != NoLevel {
+		parts = append(parts, "*="+rules.fallback.String())
+	}
+	rules.spec = strings.Join(parts, ",")
+
+	return rules, nil
+}
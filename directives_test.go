@@ -0,0 +1,162 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelDirectives(t *testing.T) {
+	t.Run("parses directives", func(t *testing.T) {
+		d, err := ParseLevelDirectives(" raft=DEBUG, http.server = warn ,, *=info")
+		require.NoError(t, err)
+
+		assert.Equal(t, "http.server=warn,raft=debug,*=info", d.String())
+
+		assert.Equal(t, Debug, d.LevelFor("raft"))
+		assert.Equal(t, Debug, d.LevelFor("raft.fsm"))
+		assert.Equal(t, Warn, d.LevelFor("http.server"))
+		assert.Equal(t, Warn, d.LevelFor("http.server.conn"))
+		assert.Equal(t, Info, d.LevelFor("http"))
+		assert.Equal(t, Info, d.LevelFor("http.serverx"))
+		assert.Equal(t, Info, d.LevelFor(""))
+	})
+
+	t.Run("treats a bare level as the fallback", func(t *testing.T) {
+		d, err := ParseLevelDirectives("raft=trace,error")
+		require.NoError(t, err)
+
+		assert.Equal(t, Trace, d.LevelFor("raft"))
+		assert.Equal(t, Error, d.LevelFor("other"))
+	})
+
+	t.Run("returns NoLevel when nothing matches", func(t *testing.T) {
+		d, err := ParseLevelDirectives("raft=trace")
+		require.NoError(t, err)
+
+		assert.Equal(t, NoLevel, d.LevelFor("http"))
+
+		d, err = ParseLevelDirectives("")
+		require.NoError(t, err)
+
+		assert.Equal(t, NoLevel, d.LevelFor("raft"))
+		assert.Equal(t, "", d.String())
+	})
+
+	t.Run("rejects invalid directives", func(t *testing.T) {
+		_, err := ParseLevelDirectives("raft=loud")
+		assert.EqualError(t, err, `invalid level "loud" in level directive "raft=loud"`)
+
+		_, err = ParseLevelDirectives("=debug")
+		assert.EqualError(t, err, `missing logger name in level directive "=debug"`)
+
+		d, err := ParseLevelDirectives("raft=debug")
+		require.NoError(t, err)
+
+		assert.Error(t, d.Set("raft"))
+		assert.Equal(t, "raft=debug", d.String())
+	})
+
+	t.Run("sets the level of named subloggers", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		d, err := ParseLevelDirectives("raft=debug,http.server=error")
+		require.NoError(t, err)
+
+		logger := New(&LoggerOptions{
+			Name:            "app",
+			Output:          &buf,
+			DisableTime:     true,
+			LevelDirectives: d,
+		})
+
+		raft := logger.Named("raft")
+		fsm := raft.Named("fsm")
+		server := logger.Named("http").Named("server")
+		client := logger.Named("http").Named("client")
+
+		assert.Equal(t, Info, logger.GetLevel())
+		assert.Equal(t, Debug, raft.GetLevel())
+		assert.Equal(t, Debug, fsm.GetLevel())
+		assert.Equal(t, Error, server.GetLevel())
+		assert.Equal(t, Info, client.GetLevel())
+
+		raft.Debug("debug from raft")
+		fsm.With("index", 1).Debug("debug from fsm")
+		server.Warn("warn from server")
+		client.Warn("warn from client")
+		logger.Debug("debug from root")
+
+		assert.Equal(t, strings.Join([]string{
+			"[DEBUG] app.raft: debug from raft",
+			"[DEBUG] app.raft.fsm: debug from fsm: index=1",
+			"[WARN]  app.http.client: warn from client",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("updates levels when the directives change", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		d, err := ParseLevelDirectives("raft=debug")
+		require.NoError(t, err)
+
+		logger := New(&LoggerOptions{
+			Output:          &buf,
+			LevelDirectives: d,
+		})
+
+		raft := logger.Named("raft")
+		http := logger.Named("http")
+
+		assert.True(t, raft.IsDebug())
+		assert.False(t, http.IsDebug())
+
+		require.NoError(t, d.Set("http=trace,*=warn"))
+
+		assert.Equal(t, Warn, logger.GetLevel())
+		assert.Equal(t, Warn, raft.GetLevel())
+		assert.Equal(t, Trace, http.GetLevel())
+
+		require.NoError(t, d.Set(""))
+
+		assert.Equal(t, Info, raft.GetLevel())
+		assert.Equal(t, Info, http.GetLevel())
+	})
+
+	t.Run("falls back to SetLevel for unmatched loggers", func(t *testing.T) {
+		d, err := ParseLevelDirectives("raft=debug")
+		require.NoError(t, err)
+
+		logger := New(&LoggerOptions{
+			LevelDirectives: d,
+		})
+
+		raft := logger.Named("raft")
+		http := logger.Named("http")
+
+		logger.SetLevel(Error)
+
+		assert.Equal(t, Debug, raft.GetLevel())
+		assert.Equal(t, Error, http.GetLevel())
+	})
+
+	t.Run("matches ResetNamed loggers by their full name", func(t *testing.T) {
+		d, err := ParseLevelDirectives("other=trace")
+		require.NoError(t, err)
+
+		logger := New(&LoggerOptions{
+			Name:            "app",
+			LevelDirectives: d,
+		})
+
+		assert.Equal(t, Info, logger.Named("other").ResetNamed("app").GetLevel())
+		assert.Equal(t, Trace, logger.ResetNamed("other").GetLevel())
+	})
+}
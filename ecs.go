@@ -0,0 +1,114 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ECSVersion is the version of the Elastic Common Schema written by
+// OutputECS.
+const ECSVersion = "8.11.0"
+
+// DefaultECSNamespace is the field args are nested under with OutputECS if no
+// other is given.
+const DefaultECSNamespace = "fields"
+
+// ECSOptions configures the OutputECS format.
+type ECSOptions struct {
+	// Namespace is the field the args of an entry are nested under, so that
+	// they can't collide with ECS fields. Defaults to DefaultECSNamespace.
+	Namespace string
+}
+
+// ecsFormat is the resolved ECSOptions for a logger.
+type ecsFormat struct {
+	namespace string
+}
+
+func newECSFormat(opts *ECSOptions) *ecsFormat {
+	if opts == nil {
+		opts = &ECSOptions{}
+	}
+
+	f := &ecsFormat{
+		namespace: opts.Namespace,
+	}
+
+	if f.namespace == "" {
+		f.namespace = DefaultECSNamespace
+	}
+
+	return f
+}
+
+// logECS writes the entry as a JSON object using ECS field names. The first
+// arg whose value is an error is written as error.message and error.type
+// rather than with the other args, and a stacktrace as error.stack_trace.
+func (l *intLogger) logECS(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	vals := l.ecsMapEntry(t, pc, name, level, msg)
+
+	pairs, stacktrace := splitArgs(implied, args)
+
+	fields := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		if err, ok := pairs[i+1].(error); ok {
+			if _, seen := vals["error.message"]; !seen {
+				vals["error.message"] = err.Error()
+				vals["error.type"] = fmt.Sprintf("%T", err)
+				continue
+			}
+		}
+
+		fields[argKey(pairs[i])] = jsonValue(pairs[i+1])
+	}
+
+	if len(fields) > 0 {
+		vals[l.ecs.namespace] = fields
+	}
+
+	if stacktrace != "" {
+		vals["error.stack_trace"] = string(stacktrace)
+	}
+
+	encoder := json.NewEncoder(l.writer)
+	encoder.SetEscapeHTML(l.jsonEscapeEnabled)
+	if err := encoder.Encode(vals); err != nil {
+		if _, ok := err.(*json.UnsupportedTypeError); ok {
+			plainVal := l.ecsMapEntry(t, pc, name, level, msg)
+			plainVal["@warn"] = errJsonUnsupportedTypeMsg
+
+			errEncoder := json.NewEncoder(l.writer)
+			errEncoder.SetEscapeHTML(l.jsonEscapeEnabled)
+			_ = errEncoder.Encode(plainVal)
+		}
+	}
+}
+
+func (l *intLogger) ecsMapEntry(t time.Time, pc uintptr, name string, level Level, msg string) map[string]any {
+	vals := map[string]any{
+		"message":     msg,
+		"log.level":   levelName(level),
+		"ecs.version": ECSVersion,
+	}
+
+	if !l.disableTime && !t.IsZero() {
+		vals["@timestamp"] = t.Format(l.timeFormat)
+	}
+
+	if name != "" {
+		vals["log.logger"] = name
+	}
+
+	if l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			vals["log.origin.file.name"] = file
+			vals["log.origin.file.line"] = line
+		}
+	}
+
+	return vals
+}
@@ -0,0 +1,86 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_ECS(t *testing.T) {
+	t.Run("uses ECS field names", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:            "app",
+			Output:          &buf,
+			OutputFormat:    OutputECS,
+			IncludeLocation: true,
+			TimeFn: func() time.Time {
+				return time.Date(2026, 3, 4, 5, 6, 7, 891234000, time.UTC)
+			},
+		})
+
+		logger.Named("http").With("peer", "10.0.0.1").Error("request failed",
+			"status", 503,
+			"error", errors.New("timeout"),
+			"cause", errors.New("dial"),
+			"message", "from args",
+			CapturedStacktrace("stack"),
+		)
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+
+		file, ok := raw["log.origin.file.name"].(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasSuffix(file, "ecs_test.go"), file)
+		assert.NotZero(t, raw["log.origin.file.line"])
+		delete(raw, "log.origin.file.name")
+		delete(raw, "log.origin.file.line")
+
+		assert.Equal(t, map[string]any{
+			"@timestamp":        "2026-03-04T05:06:07.891234Z",
+			"message":           "request failed",
+			"log.level":         "error",
+			"log.logger":        "app.http",
+			"ecs.version":       ECSVersion,
+			"error.message":     "timeout",
+			"error.type":        "*errors.errorString",
+			"error.stack_trace": "stack",
+			"fields": map[string]any{
+				"peer":    "10.0.0.1",
+				"status":  float64(503),
+				"cause":   "dial",
+				"message": "from args",
+			},
+		}, raw)
+	})
+
+	t.Run("uses the namespace", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:       &buf,
+			OutputFormat: OutputECS,
+			DisableTime:  true,
+			ECS:          &ECSOptions{Namespace: "app"},
+		})
+
+		logger.Info("hello", "a", 1)
+		logger.Info("bare")
+
+		assert.Equal(t,
+			`{"app":{"a":1},"ecs.version":"`+ECSVersion+`","log.level":"info","message":"hello"}`+"\n"+
+				`{"ecs.version":"`+ECSVersion+`","log.level":"info","message":"bare"}`+"\n",
+			buf.String())
+	})
+}
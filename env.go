@@ -0,0 +1,121 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoggerOptionsFromEnv returns a copy of base with the options set by
+// environment variables applied on top. If base is nil, the returned options
+// start out empty. The variables are named by joining prefix and the
+// setting with an underscore, so a prefix of "MYAPP" reads MYAPP_LOG_LEVEL.
+// An empty prefix reads LOG_LEVEL and so on. Unset or empty variables leave
+// the corresponding option untouched.
+//
+//	LOG_LEVEL              trace, debug, info, warn, error or off
+//	LOG_MODULE_LEVELS      level directives, e.g. "raft=debug,http=warn"
+//	LOG_FORMAT             plain, json, syslog, logfmt, gelf, otlp or ecs
+//	LOG_COLOR              off, auto or force
+//	LOG_INCLUDE_LOCATION   a boolean, as accepted by strconv.ParseBool
+//	LOG_TIME_FORMAT        a time layout, as accepted by time.Time.Format
+//	LOG_FILE               a path to append log output to
+//
+// Invalid values are reported together in the returned error, rather than
+// silently falling back to a default. When LOG_FILE is set the file is
+// created if needed and opened for appending, and is used as Output; closing
+// it is left to the caller.
+func LoggerOptionsFromEnv(prefix string, base *LoggerOptions) (*LoggerOptions, error) {
+	var opts LoggerOptions
+	if base != nil {
+		opts = *base
+	}
+
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	var errs []error
+
+	lookup := func(name string) (string, string, bool) {
+		key := prefix + name
+		val := strings.TrimSpace(os.Getenv(key))
+		return key, val, val != ""
+	}
+
+	invalid := func(key, val string, err error) {
+		errs = append(errs, fmt.Errorf("invalid value %q for %s: %w", val, key, err))
+	}
+
+	if key, val, ok := lookup("LOG_LEVEL"); ok {
+		if level := LevelFromString(val); level != NoLevel {
+			opts.Level = level
+		} else {
+			invalid(key, val, errors.New("unknown level"))
+		}
+	}
+
+	if key, val, ok := lookup("LOG_MODULE_LEVELS"); ok {
+		if d, err := ParseLevelDirectives(val); err == nil {
+			opts.LevelDirectives = d
+		} else {
+			invalid(key, val, err)
+		}
+	}
+
+	if key, val, ok := lookup("LOG_FORMAT"); ok {
+		if format, ok := OutputFormatFromString(val); ok {
+			opts.OutputFormat = format
+			opts.JSONFormat = format == OutputJSON
+		} else {
+			invalid(key, val, errors.New("unknown format"))
+		}
+	}
+
+	if key, val, ok := lookup("LOG_COLOR"); ok {
+		switch strings.ToLower(val) {
+		case "off":
+			opts.Color = ColorOff
+		case "auto":
+			opts.Color = AutoColor
+		case "force":
+			opts.Color = ForceColor
+		default:
+			invalid(key, val, errors.New("unknown color option"))
+		}
+	}
+
+	if key, val, ok := lookup("LOG_INCLUDE_LOCATION"); ok {
+		if b, err := strconv.ParseBool(val); err == nil {
+			opts.IncludeLocation = b
+		} else {
+			invalid(key, val, errors.New("not a boolean"))
+		}
+	}
+
+	if _, val, ok := lookup("LOG_TIME_FORMAT"); ok {
+		opts.TimeFormat = val
+	}
+
+	// Only open the file once everything else is known to be valid, so that
+	// an error doesn't leave it open.
+	if key, val, ok := lookup("LOG_FILE"); ok && len(errs) == 0 {
+		f, err := os.OpenFile(val, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s: %w", key, err)
+		}
+
+		opts.Output = f
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &opts, nil
+}
@@ -0,0 +1,96 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerOptionsFromEnv(t *testing.T) {
+	t.Run("applies settings from the environment", func(t *testing.T) {
+		t.Setenv("MYAPP_LOG_LEVEL", "DEBUG")
+		t.Setenv("MYAPP_LOG_MODULE_LEVELS", "raft=trace")
+		t.Setenv("MYAPP_LOG_FORMAT", "json")
+		t.Setenv("MYAPP_LOG_COLOR", "force")
+		t.Setenv("MYAPP_LOG_INCLUDE_LOCATION", "true")
+		t.Setenv("MYAPP_LOG_TIME_FORMAT", "15:04")
+
+		var buf bytes.Buffer
+
+		opts, err := LoggerOptionsFromEnv("MYAPP", &LoggerOptions{
+			Name:   "myapp",
+			Output: &buf,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "myapp", opts.Name)
+		assert.Equal(t, &buf, opts.Output)
+		assert.Equal(t, Debug, opts.Level)
+		assert.Equal(t, "raft=trace", opts.LevelDirectives.String())
+		assert.True(t, opts.JSONFormat)
+		assert.Equal(t, OutputJSON, opts.OutputFormat)
+		assert.Equal(t, ForceColor, opts.Color)
+		assert.True(t, opts.IncludeLocation)
+		assert.Equal(t, "15:04", opts.TimeFormat)
+	})
+
+	t.Run("leaves unset values alone", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "")
+
+		base := &LoggerOptions{
+			Level:      Warn,
+			JSONFormat: true,
+		}
+
+		opts, err := LoggerOptionsFromEnv("", base)
+		require.NoError(t, err)
+
+		assert.Equal(t, base, opts)
+		assert.NotSame(t, base, opts)
+
+		opts, err = LoggerOptionsFromEnv("", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, &LoggerOptions{}, opts)
+	})
+
+	t.Run("reports invalid values", func(t *testing.T) {
+		t.Setenv("MYAPP_LOG_LEVEL", "loud")
+		t.Setenv("MYAPP_LOG_FORMAT", "xml")
+		t.Setenv("MYAPP_LOG_INCLUDE_LOCATION", "sometimes")
+
+		opts, err := LoggerOptionsFromEnv("MYAPP_", nil)
+		assert.Nil(t, opts)
+		assert.EqualError(t, err, `invalid value "loud" for MYAPP_LOG_LEVEL: unknown level
+invalid value "xml" for MYAPP_LOG_FORMAT: unknown format
+invalid value "sometimes" for MYAPP_LOG_INCLUDE_LOCATION: not a boolean`)
+	})
+
+	t.Run("opens the output file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o644))
+
+		t.Setenv("MYAPP_LOG_FILE", path)
+
+		opts, err := LoggerOptionsFromEnv("MYAPP", &LoggerOptions{DisableTime: true})
+		require.NoError(t, err)
+
+		f, ok := opts.Output.(*os.File)
+		require.True(t, ok)
+		defer f.Close()
+
+		New(opts).Info("appended")
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "existing\n[INFO]  appended\n", string(data))
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxErrorDepth limits how deeply the causes of an error are written with
+// LoggerOptions.StructuredErrors, in case of a cycle.
+const maxErrorDepth = 32
+
+// ErrorCallers is implemented by errors which record the stack where they
+// were created, such as those from github.com/go-errors/errors. With
+// LoggerOptions.StructuredErrors, the stack is written along with the error.
+type ErrorCallers interface {
+	// Callers returns the program counters of the stack, as returned by
+	// runtime.Callers.
+	Callers() []uintptr
+}
+
+// errorCauses returns the errors err wraps, following both forms of Unwrap.
+func errorCauses(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+
+	return nil
+}
+
+// errorStack returns the stack recorded by err, if it has one.
+func errorStack(err error) string {
+	if c, ok := err.(ErrorCallers); ok {
+		if pcs := c.Callers(); len(pcs) > 0 {
+			return formatStacktrace(pcs)
+		}
+	}
+
+	return ""
+}
+
+// errorText renders err for the plain format as its type and message,
+// followed by its stack and causes, each indented beneath it:
+//
+//	*fmt.wrapError: connect: refused
+//	  caused by: *errors.errorString: refused
+func errorText(err error) string {
+	var sb strings.Builder
+	writeErrorText(&sb, err, "", "", 0)
+	return sb.String()
+}
+
+func writeErrorText(sb *strings.Builder, err error, indent, prefix string, depth int) {
+	if depth > 0 {
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(indent)
+	sb.WriteString(prefix)
+	fmt.Fprintf(sb, "%T: ", err)
+
+	msg := fmt.Sprintf("%v", err)
+	sb.WriteString(strings.ReplaceAll(msg, "\n", "\n"+indent+"  "))
+
+	if stack := errorStack(err); stack != "" {
+		sb.WriteByte('\n')
+		sb.WriteString(indent)
+		sb.WriteString("  stack:\n")
+		sb.WriteString(indent)
+		sb.WriteString("    ")
+
+		// Indent the file of each frame with spaces rather than a tab, which
+		// the plain format would escape.
+		stack = strings.ReplaceAll(stack, "\n\t", "\n  ")
+		sb.WriteString(strings.ReplaceAll(stack, "\n", "\n"+indent+"    "))
+	}
+
+	if depth == maxErrorDepth {
+		return
+	}
+
+	for _, cause := range errorCauses(err) {
+		if cause != nil {
+			writeErrorText(sb, cause, indent+"  ", "caused by: ", depth+1)
+		}
+	}
+}
+
+// errorObject writes err as an object with its message, type, stack and
+// causes. Causes which implement a marshaler are written by it.
+func (e *jsonEncoder) errorObject(err error) error {
+	e.begin()
+	e.field("message", err.Error())
+	e.field("type", fmt.Sprintf("%T", err))
+
+	if stack := errorStack(err); stack != "" {
+		e.field("stack", stack)
+	}
+
+	if causes := errorCauses(err); len(causes) > 0 && e.errorDepth < maxErrorDepth {
+		e.errorDepth++
+		defer func() { e.errorDepth-- }()
+
+		e.key("causes")
+		_ = e.w.WriteByte('[')
+
+		n := 0
+		for _, cause := range causes {
+			if cause == nil {
+				continue
+			}
+
+			if n > 0 {
+				_ = e.w.WriteByte(',')
+			}
+			n++
+
+			if err := e.value(cause); err != nil {
+				return err
+			}
+		}
+
+		_ = e.w.WriteByte(']')
+	}
+
+	e.end()
+
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callersError records the stack where it was created.
+type callersError struct {
+	msg string
+	pcs []uintptr
+}
+
+func newCallersError(msg string) *callersError {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	return &callersError{msg: msg, pcs: pcs[:n]}
+}
+
+func (e *callersError) Error() string { return e.msg }
+
+func (e *callersError) Callers() []uintptr { return e.pcs }
+
+func TestStructuredErrors(t *testing.T) {
+	refused := errors.New("refused")
+	dial := fmt.Errorf("dial: %w", refused)
+	connect := fmt.Errorf("connect: %w", dial)
+
+	t.Run("writes the chain as an object in JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:           &buf,
+			JSONFormat:       true,
+			DisableTime:      true,
+			StructuredErrors: true,
+		})
+
+		logger.Error("failed", "error", connect, "joined", errors.Join(refused, nil, errors.New("timeout")))
+
+		assert.Equal(t,
+			`{"@level":"error","@message":"failed",`+
+				`"error":{"message":"connect: dial: refused","type":"*fmt.wrapError","causes":[`+
+				`{"message":"dial: refused","type":"*fmt.wrapError","causes":[`+
+				`{"message":"refused","type":"*errors.errorString"}]}]},`+
+				`"joined":{"message":"refused\ntimeout","type":"*errors.joinError","causes":[`+
+				`{"message":"refused","type":"*errors.errorString"},`+
+				`{"message":"timeout","type":"*errors.errorString"}]}}`+"\n",
+			buf.String())
+	})
+
+	t.Run("writes the chain as a block in plain", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:           &buf,
+			DisableTime:      true,
+			StructuredErrors: true,
+		})
+
+		logger.Error("failed", "error", connect, "joined", errors.Join(refused, errors.New("timeout")), "simple", refused)
+
+		assert.Equal(t,
+			"[ERROR] failed:\n"+
+				"  error=\n"+
+				"  | *fmt.wrapError: connect: dial: refused\n"+
+				"  |   caused by: *fmt.wrapError: dial: refused\n"+
+				"  |     caused by: *errors.errorString: refused\n"+
+				"  \n"+
+				"  joined=\n"+
+				"  | *errors.joinError: refused\n"+
+				"  |   timeout\n"+
+				"  |   caused by: *errors.errorString: refused\n"+
+				"  |   caused by: *errors.errorString: timeout\n"+
+				"   simple=\"*errors.errorString: refused\"\n",
+			buf.String())
+	})
+
+	t.Run("writes the stack of an error which has one", func(t *testing.T) {
+		var plain, js bytes.Buffer
+
+		err := fmt.Errorf("wrapped: %w", newCallersError("boom"))
+
+		New(&LoggerOptions{
+			Output:           &plain,
+			DisableTime:      true,
+			StructuredErrors: true,
+		}).Error("failed", "error", err)
+
+		New(&LoggerOptions{
+			Output:           &js,
+			JSONFormat:       true,
+			DisableTime:      true,
+			StructuredErrors: true,
+		}).Error("failed", "error", err)
+
+		assert.Contains(t, plain.String(), "\n  |   caused by: *hclog.callersError: boom\n  |     stack:\n  |       github.com/hashicorp/go-hclog.newCallersError\n  |         ")
+		assert.Regexp(t, `\n  \|         \S*/errorchain_test\.go:\d+\n`, plain.String())
+
+		var entry struct {
+			Error struct {
+				Causes []struct {
+					Message string `json:"message"`
+					Stack   string `json:"stack"`
+				} `json:"causes"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(js.Bytes(), &entry))
+		require.Len(t, entry.Error.Causes, 1)
+		assert.Equal(t, "boom", entry.Error.Causes[0].Message)
+		assert.True(t, strings.HasPrefix(entry.Error.Causes[0].Stack, "github.com/hashicorp/go-hclog.newCallersError\n\t"), entry.Error.Causes[0].Stack)
+	})
+
+	t.Run("uses marshalers", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:           &buf,
+			JSONFormat:       true,
+			DisableTime:      true,
+			StructuredErrors: true,
+		})
+
+		logger.Error("failed", "error", fmt.Errorf("wrapped: %w", jsonMarshalerError{}), "direct", jsonMarshalerError{})
+
+		assert.Equal(t,
+			`{"@level":"error","@message":"failed","error":{"message":"wrapped: plain","type":"*fmt.wrapError","causes":[{"code":42}]},"direct":{"code":42}}`+"\n",
+			buf.String())
+	})
+
+	t.Run("is off by default", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		logger.Error("failed", "error", connect)
+
+		assert.Equal(t, `{"@level":"error","@message":"failed","error":"connect: dial: refused"}`+"\n", buf.String())
+	})
+}
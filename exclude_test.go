@@ -4,6 +4,7 @@
 package hclog
 
 import (
+	"bytes"
 	"regexp"
 	"testing"
 
@@ -55,4 +56,28 @@ func TestExclude(t *testing.T) {
 		assert.False(t, ef.Exclude(Info, "qux foo: bar"))
 
 	})
+
+	t.Run("is given the args as they were logged", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			got []any
+		)
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+			Redact:      &RedactOptions{},
+			Exclude: func(level Level, msg string, args ...any) bool {
+				got = args
+				return msg == "excluded"
+			},
+		})
+
+		WithGroup(logger, "http").Info("excluded", "password", "hunter2")
+		assert.Equal(t, []any{"password", "hunter2"}, got)
+		assert.Empty(t, buf.String())
+
+		WithGroup(logger, "http").Info("written", "password", "hunter2")
+		assert.Equal(t, "[INFO]  written: http.password=[REDACTED]\n", buf.String())
+	})
 }
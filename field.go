@@ -0,0 +1,329 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+type fieldKind uint8
+
+const (
+	fieldString fieldKind = iota
+	fieldInt64
+	fieldUint64
+	fieldFloat64
+	fieldBool
+	fieldDuration
+	fieldErr
+	fieldAny
+	fieldGroup
+)
+
+// Field is a key and value passed as a single arg to the Logger methods, in
+// place of a key/value pair. Fields can be mixed freely with key/value pairs:
+//
+//	logger.Info("request", hclog.String("method", m), "status", code, hclog.Duration("elapsed", d))
+//
+// A Field is written exactly as the equivalent key/value pair is, but the
+// plain and JSON formats render its value directly from its type, without
+// reflection or fmt. Fields are created with the typed constructors such as
+// String and Int.
+//
+// A Field passed to a Logger method is boxed into an interface like the value
+// of a pair is, which allocates even for an entry below the level of the
+// logger. To log Fields without that cost, pass them to LogFields on a Logger
+// which implements FieldLogger; they're only boxed once the entry is going to
+// be written.
+type Field struct {
+	Key string
+
+	kind fieldKind
+	num  uint64
+	str  string
+	val  any
+}
+
+// FieldLogger is implemented by Loggers which can log Fields without boxing
+// them for entries which aren't written. The Loggers returned by New,
+// NewInterceptLogger, NewNullLogger and FromSlogHandler implement it:
+//
+//	if fl, ok := logger.(hclog.FieldLogger); ok {
+//		fl.LogFields(hclog.Debug, "request", hclog.String("method", m), hclog.Int("status", code))
+//	}
+type FieldLogger interface {
+	// LogFields emits the message and fields at the given level, as Log does
+	// with the fields as its args.
+	LogFields(level Level, msg string, fields ...Field)
+}
+
+var (
+	_ FieldLogger = &intLogger{}
+	_ FieldLogger = &interceptLogger{}
+	_ FieldLogger = &nullLogger{}
+	_ FieldLogger = &slogLogger{}
+)
+
+// fieldArgs returns fields as args.
+func fieldArgs(fields []Field) []any {
+	args := make([]any, len(fields))
+	for i, f := range fields {
+		args[i] = f
+	}
+
+	return args
+}
+
+// String returns a Field for a string value.
+func String(key, val string) Field {
+	return Field{Key: key, kind: fieldString, str: val}
+}
+
+// Int returns a Field for an int value.
+func Int(key string, val int) Field {
+	return Int64(key, int64(val))
+}
+
+// Int64 returns a Field for an int64 value.
+func Int64(key string, val int64) Field {
+	return Field{Key: key, kind: fieldInt64, num: uint64(val)}
+}
+
+// Uint64 returns a Field for a uint64 value.
+func Uint64(key string, val uint64) Field {
+	return Field{Key: key, kind: fieldUint64, num: val}
+}
+
+// Float64 returns a Field for a float64 value.
+func Float64(key string, val float64) Field {
+	return Field{Key: key, kind: fieldFloat64, num: math.Float64bits(val)}
+}
+
+// Bool returns a Field for a bool value.
+func Bool(key string, val bool) Field {
+	f := Field{Key: key, kind: fieldBool}
+	if val {
+		f.num = 1
+	}
+	return f
+}
+
+// Duration returns a Field for a time.Duration value.
+func Duration(key string, val time.Duration) Field {
+	return Field{Key: key, kind: fieldDuration, num: uint64(val)}
+}
+
+// Err returns a Field for an error, with the key "error".
+func Err(err error) Field {
+	return Field{Key: "error", kind: fieldErr, val: err}
+}
+
+// Any returns a Field for a value of any type, which is written as it would
+// be as the value of a key/value pair.
+func Any(key string, val any) Field {
+	return Field{Key: key, kind: fieldAny, val: val}
+}
+
+// Value returns the value of the field, as it would be passed in a key/value
+// pair. The value of a field created by Group is its args.
+func (f Field) Value() any {
+	switch f.kind {
+	case fieldString:
+		return f.str
+	case fieldInt64:
+		return int64(f.num)
+	case fieldUint64:
+		return f.num
+	case fieldFloat64:
+		return math.Float64frombits(f.num)
+	case fieldBool:
+		return f.num != 0
+	case fieldDuration:
+		return time.Duration(f.num)
+	default:
+		return f.val
+	}
+}
+
+// group returns the args of a field created by Group, and whether it is one.
+func (f Field) group() ([]any, bool) {
+	if f.kind != fieldGroup {
+		return nil, false
+	}
+
+	args, _ := f.val.([]any)
+	return args, true
+}
+
+// numeric reports whether the value of the field is an integer or bool, which
+// can be written with appendNumber. Floats aren't included, as an exponent or
+// infinity is quoted.
+func (f Field) numeric() bool {
+	switch f.kind {
+	case fieldInt64, fieldUint64, fieldBool:
+		return true
+	default:
+		return false
+	}
+}
+
+// appendNumber appends the value of a numeric field to b as plainValue
+// renders it.
+func (f Field) appendNumber(b []byte) []byte {
+	switch f.kind {
+	case fieldInt64:
+		return strconv.AppendInt(b, int64(f.num), 10)
+	case fieldUint64:
+		return strconv.AppendUint(b, f.num, 10)
+	case fieldBool:
+		return strconv.AppendBool(b, f.num != 0)
+	default:
+		return b
+	}
+}
+
+// plainFieldValue renders the value of a field as plainValue renders the
+// equivalent value.
+func (l *intLogger) plainFieldValue(f Field) (val string, raw bool) {
+	switch f.kind {
+	case fieldString:
+		if f.str == "" {
+			return `""`, true
+		}
+		return f.str, false
+	case fieldInt64:
+		return strconv.FormatInt(int64(f.num), 10), false
+	case fieldUint64:
+		return strconv.FormatUint(f.num, 10), false
+	case fieldFloat64:
+		return strconv.FormatFloat(math.Float64frombits(f.num), 'g', -1, 64), false
+	case fieldBool:
+		return strconv.FormatBool(f.num != 0), false
+	case fieldDuration:
+		return time.Duration(f.num).String(), false
+	default:
+		return l.plainValue(f.val)
+	}
+}
+
+// fieldValue writes the value of a field as value writes the equivalent
+// value.
+func (e *jsonEncoder) fieldValue(f Field) error {
+	b := e.w.b.AvailableBuffer()
+
+	switch f.kind {
+	case fieldString:
+		writeJSONString(e.w, f.str, e.escapeHTML)
+	case fieldInt64, fieldDuration:
+		_, _ = e.w.Write(strconv.AppendInt(b, int64(f.num), 10))
+	case fieldUint64:
+		_, _ = e.w.Write(strconv.AppendUint(b, f.num, 10))
+	case fieldFloat64:
+		return e.float(math.Float64frombits(f.num), 64)
+	case fieldBool:
+		_, _ = e.w.Write(strconv.AppendBool(b, f.num != 0))
+	case fieldGroup:
+		args, _ := f.group()
+		e.begin()
+		if err := e.args(args); err != nil {
+			return err
+		}
+		e.end()
+	default:
+		return e.value(f.val)
+	}
+
+	return nil
+}
+
+// nextArg returns the key and value of the arg starting at args[i], and the
+// index of the arg after it. A Field is its own key and value, and is
+// returned as the value so that its contents aren't boxed again. Otherwise
+// the arg is a key followed by its value, and a trailing value without a key
+// is given MissingKey.
+func nextArg(args []any, i int) (key string, val any, next int) {
+	if f, ok := args[i].(Field); ok {
+		return f.Key, args[i], i + 1
+	}
+
+	if i+1 == len(args) {
+		return MissingKey, args[i], i + 1
+	}
+
+	return argKey(args[i]), args[i+1], i + 2
+}
+
+// splitStacktrace removes a trailing CapturedStacktrace that isn't the value
+// of a key/value pair from args.
+func splitStacktrace(args []any) ([]any, CapturedStacktrace) {
+	if len(args) == 0 {
+		return args, ""
+	}
+
+	cs, ok := args[len(args)-1].(CapturedStacktrace)
+	if !ok {
+		return args, ""
+	}
+
+	if !trailingValue(args) {
+		return args, ""
+	}
+
+	return args[:len(args)-1], cs
+}
+
+// trailingValue reports whether the last of args is a value without a key.
+func trailingValue(args []any) bool {
+	i := 0
+	for i < len(args)-1 {
+		_, _, i = nextArg(args, i)
+	}
+
+	if i != len(args)-1 {
+		return false
+	}
+
+	_, ok := args[i].(Field)
+	return !ok
+}
+
+// expandFields returns args with each Field other than a group replaced by
+// its key and value. args is returned as is if it has no such fields.
+func expandFields(args []any) []any {
+	var expanded []any
+
+	for i := 0; i < len(args); {
+		if f, ok := args[i].(Field); ok {
+			if _, ok := f.group(); ok {
+				if expanded != nil {
+					expanded = append(expanded, f)
+				}
+				i++
+				continue
+			}
+
+			if expanded == nil {
+				expanded = make([]any, 0, len(args)+len(args)-i)
+				expanded = append(expanded, args[:i]...)
+			}
+			expanded = append(expanded, f.Key, f.Value())
+			i++
+			continue
+		}
+
+		next := min(i+2, len(args))
+		if expanded != nil {
+			expanded = append(expanded, args[i:next]...)
+		}
+		i = next
+	}
+
+	if expanded == nil {
+		return args
+	}
+
+	return expanded
+}
@@ -0,0 +1,232 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFields(t *testing.T) {
+	err := errors.New("connection refused")
+
+	cases := []struct {
+		name   string
+		fields []any
+		pairs  []any
+	}{
+		{
+			name:   "string",
+			fields: []any{String("a", "b"), String("empty", ""), String("spaced", "a b"), String("lines", "one\ntwo")},
+			pairs:  []any{"a", "b", "empty", "", "spaced", "a b", "lines", "one\ntwo"},
+		},
+		{
+			name:   "numbers",
+			fields: []any{Int("i", -12), Int64("i64", math.MinInt64), Uint64("u64", math.MaxUint64), Float64("f", 1.5), Float64("big", 1e21)},
+			pairs:  []any{"i", -12, "i64", int64(math.MinInt64), "u64", uint64(math.MaxUint64), "f", 1.5, "big", 1e21},
+		},
+		{
+			name:   "bool and duration",
+			fields: []any{Bool("yes", true), Bool("no", false), Duration("elapsed", 1500*time.Millisecond)},
+			pairs:  []any{"yes", true, "no", false, "elapsed", 1500 * time.Millisecond},
+		},
+		{
+			name:   "errors",
+			fields: []any{Err(err), Err(nil)},
+			pairs:  []any{"error", err, "error", nil},
+		},
+		{
+			name:   "any",
+			fields: []any{Any("list", []int{1, 2}), Any("hex", Hex(16)), Any("map", map[string]int{"a": 1})},
+			pairs:  []any{"list", []int{1, 2}, "hex", Hex(16), "map", map[string]int{"a": 1}},
+		},
+		{
+			name:   "mixed with pairs",
+			fields: []any{"a", 1, String("b", "two"), "c", 3, Int("d", 4)},
+			pairs:  []any{"a", 1, "b", "two", "c", 3, "d", 4},
+		},
+		{
+			name:   "trailing value",
+			fields: []any{String("a", "b"), "extra"},
+			pairs:  []any{"a", "b", "extra"},
+		},
+		{
+			name:   "trailing stacktrace",
+			fields: []any{Int("a", 1), CapturedStacktrace("stack")},
+			pairs:  []any{"a", 1, CapturedStacktrace("stack")},
+		},
+		{
+			name:   "field as a value",
+			fields: []any{"a", Int("ignored", 1)},
+			pairs:  []any{"a", 1},
+		},
+	}
+
+	formats := []OutputFormat{OutputPlain, OutputJSON, OutputLogfmt, OutputECS}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, format := range formats {
+				var fieldsBuf, pairsBuf bytes.Buffer
+
+				opts := LoggerOptions{
+					Name:         "test",
+					OutputFormat: format,
+					DisableTime:  true,
+				}
+
+				opts.Output = &fieldsBuf
+				New(&opts).With(String("w", "x")).Info("entry", c.fields...)
+
+				opts.Output = &pairsBuf
+				New(&opts).With("w", "x").Info("entry", c.pairs...)
+
+				assert.Equal(t, pairsBuf.String(), fieldsBuf.String(), format.String())
+			}
+		})
+	}
+
+	t.Run("keeps fields passed to With sorted", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+		})
+
+		logger.With(String("b", "2"), "a", 1).With(Int("c", 3)).Info("entry")
+
+		assert.Equal(t, "[INFO]  entry: a=1 b=2 c=3\n", buf.String())
+		assert.Equal(t, []any{"a", 1, "b", "2", "c", int64(3)}, logger.With(String("b", "2"), "a", 1).With(Int("c", 3)).ImpliedArgs())
+	})
+
+	t.Run("returns the value", func(t *testing.T) {
+		assert.Equal(t, "b", String("a", "b").Value())
+		assert.Equal(t, int64(-1), Int("a", -1).Value())
+		assert.Equal(t, uint64(math.MaxUint64), Uint64("a", math.MaxUint64).Value())
+		assert.Equal(t, 0.25, Float64("a", 0.25).Value())
+		assert.Equal(t, true, Bool("a", true).Value())
+		assert.Equal(t, time.Second, Duration("a", time.Second).Value())
+		assert.Equal(t, nil, Err(nil).Value())
+		assert.Equal(t, []int{1}, Any("a", []int{1}).Value())
+	})
+
+	t.Run("logs fields with LogFields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:          &buf,
+			Level:           Info,
+			DisableTime:     true,
+			IncludeLocation: true,
+		})
+
+		fl := logger.(FieldLogger)
+		fl.LogFields(Debug, "filtered", Int("a", 1))
+		fl.LogFields(Info, "entry", String("a", "b"), Int("c", 3))
+
+		assert.Regexp(t, `^\[INFO\]  \S*/field_test\.go:\d+: entry: a=b c=3\n$`, buf.String())
+	})
+
+	t.Run("logs fields to sinks with LogFields", func(t *testing.T) {
+		var buf, sinkBuf bytes.Buffer
+
+		logger := NewInterceptLogger(&LoggerOptions{
+			Output:      &buf,
+			Level:       Info,
+			DisableTime: true,
+		})
+
+		logger.RegisterSink(NewSinkAdapter(&LoggerOptions{
+			Output:      &sinkBuf,
+			Level:       Debug,
+			DisableTime: true,
+		}))
+
+		logger.(FieldLogger).LogFields(Debug, "entry", String("a", "b"))
+
+		assert.Empty(t, buf.String())
+		assert.Equal(t, "[DEBUG] entry: a=b\n", sinkBuf.String())
+	})
+
+}
+
+func BenchmarkFields(b *testing.B) {
+	// The values aren't constants, as they wouldn't be in practice, so that
+	// passing them as pairs boxes them.
+	v := struct {
+		name    string
+		count   int
+		ratio   float64
+		ok      bool
+		elapsed time.Duration
+		err     error
+	}{"foo", 4096, 0.75, true, 1500 * time.Millisecond, errors.New("boom")}
+
+	for _, format := range []OutputFormat{OutputPlain, OutputJSON} {
+		logger := New(&LoggerOptions{
+			Name:         "test",
+			Level:        Info,
+			Output:       io.Discard,
+			OutputFormat: format,
+		})
+
+		for _, level := range []Level{Info, Debug} {
+			name := format.String() + "/written"
+			if level < Info {
+				name = format.String() + "/filtered"
+			}
+
+			b.Run(name+"/pairs", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					logger.Log(level, "this is some message",
+						"name", v.name,
+						"count", v.count,
+						"ratio", v.ratio,
+						"ok", v.ok,
+						"elapsed", v.elapsed,
+						"error", v.err,
+					)
+				}
+			})
+
+			b.Run(name+"/fields", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					logger.Log(level, "this is some message",
+						String("name", v.name),
+						Int("count", v.count),
+						Float64("ratio", v.ratio),
+						Bool("ok", v.ok),
+						Duration("elapsed", v.elapsed),
+						Err(v.err),
+					)
+				}
+			})
+
+			b.Run(name+"/logfields", func(b *testing.B) {
+				fl := logger.(FieldLogger)
+
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					fl.LogFields(level, "this is some message",
+						String("name", v.name),
+						Int("count", v.count),
+						Float64("ratio", v.ratio),
+						Bool("ok", v.ok),
+						Duration("elapsed", v.elapsed),
+						Err(v.err),
+					)
+				}
+			})
+		}
+	}
+}
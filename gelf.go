@@ -0,0 +1,155 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GELFOptions configures the OutputGELF format.
+type GELFOptions struct {
+	// Host to identify the machine by. Defaults to os.Hostname.
+	Host string
+}
+
+// gelfFormat is the resolved GELFOptions for a logger.
+type gelfFormat struct {
+	host string
+}
+
+func newGELFFormat(opts *GELFOptions) *gelfFormat {
+	if opts == nil {
+		opts = &GELFOptions{}
+	}
+
+	f := &gelfFormat{
+		host: opts.Host,
+	}
+
+	if f.host == "" {
+		f.host, _ = os.Hostname()
+	}
+
+	return f
+}
+
+// logGELF writes the entry as a GELF 1.1 message. The level is written as a
+// syslog severity, the name as the _module field and each arg as an
+// additional field prefixed with an underscore, or with two if that would
+// replace one of the other fields. GELF only permits strings and numbers as
+// field values, so anything else is written as in the plain format.
+func (l *intLogger) logGELF(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	pairs, stacktrace := splitArgs(implied, args)
+
+	if msg == "" {
+		// short_message is required to be non-empty
+		msg = "-"
+	}
+
+	vals := map[string]any{
+		"version":       "1.1",
+		"host":          l.gelf.host,
+		"short_message": msg,
+		"level":         syslogSeverity(level),
+	}
+
+	if !l.disableTime && !t.IsZero() {
+		vals["timestamp"] = json.Number(strconv.FormatFloat(float64(t.UnixMicro())/1e6, 'f', -1, 64))
+	}
+
+	if stacktrace != "" {
+		vals["full_message"] = string(stacktrace)
+	}
+
+	if name != "" {
+		vals["_module"] = name
+	}
+
+	if l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			vals["_caller"] = fmt.Sprintf("%s:%d", file, line)
+		}
+	}
+
+	fields := maps.Clone(vals)
+
+	for i := 0; i < len(pairs); i += 2 {
+		// An arg such as module can't replace the field of the same name,
+		// so it gets another underscore, as id does.
+		key := gelfFieldName(argKey(pairs[i]))
+		if _, ok := vals[key]; ok {
+			key = "_" + key
+		}
+
+		fields[key] = l.gelfValue(pairs[i+1])
+	}
+
+	encoder := json.NewEncoder(l.writer)
+	encoder.SetEscapeHTML(l.jsonEscapeEnabled)
+
+	if err := encoder.Encode(fields); err != nil {
+		// Write the entry without its args, as the JSON format does.
+		vals["_warn"] = errJsonUnsupportedTypeMsg
+		_ = encoder.Encode(vals)
+	}
+}
+
+// gelfFieldName returns the name of the additional field for key, which is
+// limited to letters, digits, '_', '-' and '.'. The name _id is reserved, so
+// an id key is written as __id.
+func gelfFieldName(key string) string {
+	b := make([]byte, 0, len(key)+1)
+	b = append(b, '_')
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '-', c == '.':
+			b = append(b, c)
+		default:
+			b = append(b, '_')
+		}
+	}
+
+	if string(b) == "_id" {
+		return "__id"
+	}
+
+	return string(b)
+}
+
+// gelfValue converts an arg value to a string or number.
+func (l *intLogger) gelfValue(v any) any {
+	switch st := v.(type) {
+	case string:
+		return st
+	case Quote:
+		return string(st)
+	case error:
+		return st.Error()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return st
+	case float32:
+		return gelfFloat(float64(st))
+	case float64:
+		return gelfFloat(st)
+	default:
+		val, _ := l.plainValue(st)
+		return val
+	}
+}
+
+// gelfFloat returns f, or f as a string if it can't be represented in JSON.
+func gelfFloat(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return f
+}
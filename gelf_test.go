@@ -0,0 +1,97 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_GELF(t *testing.T) {
+	t.Run("maps entries to GELF fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:         "app",
+			Output:       &buf,
+			OutputFormat: OutputGELF,
+			GELF:         &GELFOptions{Host: "host1"},
+			TimeFn: func() time.Time {
+				return time.Date(2026, 3, 4, 5, 6, 7, 891000000, time.UTC)
+			},
+		})
+
+		logger.Named("http").With("id", 7).Error("request failed",
+			"status", 503,
+			"error", errors.New("timeout"),
+			"bad key!", true,
+			"ratio", math.Inf(1),
+			CapturedStacktrace("stack"),
+		)
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+
+		assert.Equal(t, map[string]any{
+			"version":       "1.1",
+			"host":          "host1",
+			"short_message": "request failed",
+			"full_message":  "stack",
+			"timestamp":     1772600767.891,
+			"level":         float64(3),
+			"_module":       "app.http",
+			"__id":          float64(7),
+			"_status":       float64(503),
+			"_error":        "timeout",
+			"_bad_key_":     "true",
+			"_ratio":        "+Inf",
+		}, raw)
+	})
+
+	t.Run("fills in an empty message", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:       &buf,
+			OutputFormat: OutputGELF,
+			GELF:         &GELFOptions{Host: "host1"},
+			DisableTime:  true,
+		})
+
+		logger.Debug("hidden")
+		logger.Warn("")
+
+		assert.Equal(t, `{"host":"host1","level":4,"short_message":"-","version":"1.1"}`+"\n", buf.String())
+	})
+
+	t.Run("keeps args from replacing other fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:            "app",
+			Output:          &buf,
+			OutputFormat:    OutputGELF,
+			GELF:            &GELFOptions{Host: "host1"},
+			DisableTime:     true,
+			IncludeLocation: true,
+		})
+
+		logger.Info("hello", "module", "mine", "caller", "me")
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+
+		assert.Equal(t, "app", raw["_module"])
+		assert.Contains(t, raw["_caller"], "gelf_test.go:")
+		assert.Equal(t, "mine", raw["__module"])
+		assert.Equal(t, "me", raw["__caller"])
+	})
+}
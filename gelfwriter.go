@@ -0,0 +1,239 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+)
+
+// GELFCompression is how a GELFWriter compresses messages sent over UDP.
+type GELFCompression uint8
+
+const (
+	// GELFCompressNone sends messages uncompressed.
+	GELFCompressNone GELFCompression = iota
+	// GELFCompressGzip compresses messages with gzip.
+	GELFCompressGzip
+	// GELFCompressZlib compresses messages with zlib.
+	GELFCompressZlib
+)
+
+const (
+	// DefaultGELFChunkSize is the size of the UDP datagrams a GELFWriter
+	// sends if no other is given, which is suitable for most networks.
+	DefaultGELFChunkSize = 1420
+
+	// gelfMaxChunks is the most chunks a message may be split into.
+	gelfMaxChunks = 128
+
+	// gelfChunkHeaderSize is the size of the magic bytes, message id,
+	// sequence number and sequence count before each chunk.
+	gelfChunkHeaderSize = 12
+)
+
+// GELFWriterOptions can be used to configure a new GELFWriter.
+type GELFWriterOptions struct {
+	// ChunkSize is the maximum size of a UDP datagram, including the chunk
+	// header. Defaults to DefaultGELFChunkSize.
+	ChunkSize int
+
+	// Compression applied to messages sent over UDP. Messages sent over TCP
+	// are never compressed, as Graylog doesn't support it.
+	Compression GELFCompression
+}
+
+// GELFWriter sends each write to a Graylog server as a single GELF message,
+// for use as LoggerOptions.Output together with OutputGELF. The logger writes
+// each entry with a single call, so an entry always becomes one message.
+//
+// Over "udp" messages are optionally compressed, and split into chunks if
+// larger than the chunk size. Over "tcp" messages are terminated with a null
+// byte.
+type GELFWriter struct {
+	network     string
+	addr        string
+	chunkSize   int
+	compression GELFCompression
+
+	mu   sync.Mutex
+	conn net.Conn
+	buf  bytes.Buffer
+}
+
+// NewGELFWriter connects to the Graylog GELF input at addr. network is one of
+// "udp", "udp4", "udp6", "tcp", "tcp4" or "tcp6".
+func NewGELFWriter(network, addr string, opts *GELFWriterOptions) (*GELFWriter, error) {
+	switch network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("unsupported GELF network %q", network)
+	}
+
+	if opts == nil {
+		opts = &GELFWriterOptions{}
+	}
+
+	w := &GELFWriter{
+		network:     network,
+		addr:        addr,
+		chunkSize:   opts.ChunkSize,
+		compression: opts.Compression,
+	}
+
+	if w.chunkSize <= 0 {
+		w.chunkSize = DefaultGELFChunkSize
+	}
+
+	if w.chunkSize <= gelfChunkHeaderSize {
+		return nil, fmt.Errorf("GELF chunk size %d is too small", w.chunkSize)
+	}
+
+	conn, err := net.Dial(w.network, w.addr)
+	if err != nil {
+		return nil, err
+	}
+	w.conn = conn
+
+	return w, nil
+}
+
+func (w *GELFWriter) stream() bool {
+	switch w.network {
+	case "tcp", "tcp4", "tcp6":
+		return true
+	default:
+		return false
+	}
+}
+
+// Write sends p as a single message, without its trailing newline. If sending
+// over TCP fails, the connection is re-established and the message sent again
+// once.
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg := bytes.TrimSuffix(p, []byte("\n"))
+
+	var err error
+	if w.stream() {
+		err = w.writeStream(msg)
+	} else {
+		err = w.writeDatagrams(msg)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *GELFWriter) writeStream(msg []byte) error {
+	w.buf.Reset()
+	w.buf.Write(msg)
+	w.buf.WriteByte(0)
+
+	if w.conn != nil {
+		if _, err := w.conn.Write(w.buf.Bytes()); err == nil {
+			return nil
+		}
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	conn, err := net.Dial(w.network, w.addr)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+
+	_, err = w.conn.Write(w.buf.Bytes())
+	return err
+}
+
+func (w *GELFWriter) writeDatagrams(msg []byte) error {
+	if w.conn == nil {
+		return net.ErrClosed
+	}
+
+	w.buf.Reset()
+	if err := w.compress(msg); err != nil {
+		return err
+	}
+	data := w.buf.Bytes()
+
+	if len(data) <= w.chunkSize {
+		_, err := w.conn.Write(data)
+		return err
+	}
+
+	size := w.chunkSize - gelfChunkHeaderSize
+	count := (len(data) + size - 1) / size
+	if count > gelfMaxChunks {
+		return fmt.Errorf("GELF message of %d bytes needs more than %d chunks", len(data), gelfMaxChunks)
+	}
+
+	chunk := make([]byte, 0, w.chunkSize)
+	id := rand.Uint64()
+
+	for seq := 0; seq < count; seq++ {
+		end := min((seq+1)*size, len(data))
+
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = binary.BigEndian.AppendUint64(chunk, id)
+		chunk = append(chunk, byte(seq), byte(count))
+		chunk = append(chunk, data[seq*size:end]...)
+
+		if _, err := w.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compress writes msg to the buffer, compressed as configured.
+func (w *GELFWriter) compress(msg []byte) error {
+	var zw io.WriteCloser
+	switch w.compression {
+	case GELFCompressGzip:
+		zw = gzip.NewWriter(&w.buf)
+	case GELFCompressZlib:
+		zw = zlib.NewWriter(&w.buf)
+	default:
+		w.buf.Write(msg)
+		return nil
+	}
+
+	if _, err := zw.Write(msg); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Close closes the connection to the server.
+func (w *GELFWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
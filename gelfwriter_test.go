@@ -0,0 +1,161 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGELFWriter(t *testing.T) {
+	newLogger := func(w *GELFWriter) Logger {
+		return New(&LoggerOptions{
+			Name:         "app",
+			Output:       w,
+			OutputFormat: OutputGELF,
+			GELF:         &GELFOptions{Host: "host1"},
+			DisableTime:  true,
+		})
+	}
+
+	// readMessage reads datagrams until a complete message is received,
+	// reassembling chunks.
+	readMessage := func(t *testing.T, conn net.PacketConn) []byte {
+		t.Helper()
+
+		var chunks [][]byte
+		for {
+			buf := make([]byte, 65536)
+			n, _, err := conn.ReadFrom(buf)
+			require.NoError(t, err)
+			buf = buf[:n]
+
+			if n < 2 || buf[0] != 0x1e || buf[1] != 0x0f {
+				return buf
+			}
+
+			seq, count := int(buf[10]), int(buf[11])
+			if chunks == nil {
+				chunks = make([][]byte, count)
+			}
+			chunks[seq] = buf[12:]
+
+			complete := true
+			for _, c := range chunks {
+				complete = complete && c != nil
+			}
+			if complete {
+				return bytes.Join(chunks, nil)
+			}
+		}
+	}
+
+	t.Run("sends small messages in one datagram", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		w, err := NewGELFWriter("udp", conn.LocalAddr().String(), nil)
+		require.NoError(t, err)
+		defer w.Close()
+
+		newLogger(w).Info("hello", "a", 1)
+
+		assert.Equal(t,
+			`{"_a":1,"_module":"app","host":"host1","level":6,"short_message":"hello","version":"1.1"}`,
+			string(readMessage(t, conn)))
+	})
+
+	t.Run("chunks and compresses large messages", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		for _, compression := range []GELFCompression{GELFCompressNone, GELFCompressGzip, GELFCompressZlib} {
+			w, err := NewGELFWriter("udp", conn.LocalAddr().String(), &GELFWriterOptions{
+				ChunkSize:   100,
+				Compression: compression,
+			})
+			require.NoError(t, err)
+
+			// Random enough not to compress into a single chunk.
+			var sb strings.Builder
+			for i := 0; i < 200; i++ {
+				sb.WriteString(string(rune('a' + (i*7919)%26)))
+				sb.WriteString(string(rune('0' + (i*104729)%10)))
+			}
+			newLogger(w).Info("big", "data", sb.String())
+			require.NoError(t, w.Close())
+
+			data := readMessage(t, conn)
+
+			var r io.Reader = bytes.NewReader(data)
+			switch compression {
+			case GELFCompressGzip:
+				r, err = gzip.NewReader(r)
+				require.NoError(t, err)
+			case GELFCompressZlib:
+				r, err = zlib.NewReader(r)
+				require.NoError(t, err)
+			}
+
+			var raw map[string]any
+			require.NoError(t, json.NewDecoder(r).Decode(&raw))
+			assert.Equal(t, sb.String(), raw["_data"])
+		}
+	})
+
+	t.Run("terminates messages with a null byte over tcp", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		w, err := NewGELFWriter("tcp", ln.Addr().String(), &GELFWriterOptions{
+			Compression: GELFCompressGzip,
+		})
+		require.NoError(t, err)
+		defer w.Close()
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		logger := newLogger(w)
+		logger.Info("one")
+		logger.Warn("two\nlines")
+
+		r := bufio.NewReader(conn)
+		for _, want := range []string{"one", "two\nlines"} {
+			msg, err := r.ReadBytes(0)
+			require.NoError(t, err)
+
+			var raw map[string]any
+			require.NoError(t, json.Unmarshal(bytes.TrimSuffix(msg, []byte{0}), &raw))
+			assert.Equal(t, want, raw["short_message"])
+		}
+	})
+
+	t.Run("rejects messages needing too many chunks", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		w, err := NewGELFWriter("udp", conn.LocalAddr().String(), &GELFWriterOptions{ChunkSize: 13})
+		require.NoError(t, err)
+		defer w.Close()
+
+		_, err = w.Write(bytes.Repeat([]byte("x"), 129))
+		assert.EqualError(t, err, "GELF message of 129 bytes needs more than 128 chunks")
+	})
+}
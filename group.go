@@ -0,0 +1,216 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"sort"
+)
+
+// Group returns a Field which nests the given key/value pairs and Fields
+// under name, so that they can't collide with other args of the same name:
+//
+//	logger.Info("request", hclog.Group("req", "id", 1, "path", "/"))
+//
+// The plain format writes them with their keys prefixed by the name of the
+// group and a dot, as req.id=1 req.path=/, and the JSON format as a nested
+// object, as "req":{"id":1,"path":"/"}. Formats without nesting write them
+// with dotted keys as the plain format does. A group without args is omitted.
+func Group(name string, args ...any) Field {
+	return Field{Key: name, kind: fieldGroup, val: args}
+}
+
+// GroupLogger is implemented by Loggers which can nest args under a group.
+// The Loggers returned by New, NewInterceptLogger, NewNullLogger and
+// FromSlogHandler implement it. Use WithGroup to call it on any Logger.
+type GroupLogger interface {
+	// WithGroup creates a sublogger that nests the key/value pairs given to
+	// With and to each message under the named group, as Group does.
+	WithGroup(name string) Logger
+}
+
+var (
+	_ GroupLogger = &intLogger{}
+	_ GroupLogger = &interceptLogger{}
+	_ GroupLogger = &nullLogger{}
+	_ GroupLogger = &slogLogger{}
+)
+
+// WithGroup returns a sublogger of l which nests the key/value pairs given
+// to With and to each message under the named group, if l implements
+// GroupLogger. Otherwise l is returned as is.
+func WithGroup(l Logger, name string) Logger {
+	if gl, ok := l.(GroupLogger); ok {
+		return gl.WithGroup(name)
+	}
+
+	return l
+}
+
+// wrapGroups returns args nested in the given groups, outermost first. A
+// trailing CapturedStacktrace is left outside of the groups.
+func wrapGroups(groups []string, args []any) []any {
+	if len(groups) == 0 || len(args) == 0 {
+		return args
+	}
+
+	args, stacktrace := splitStacktrace(args)
+	if len(args) == 0 {
+		return []any{stacktrace}
+	}
+
+	g := Group(groups[len(groups)-1], args...)
+	for i := len(groups) - 2; i >= 0; i-- {
+		g = Group(groups[i], g)
+	}
+
+	if stacktrace != "" {
+		return []any{g, stacktrace}
+	}
+
+	return []any{g}
+}
+
+// joinGroups returns args appended to implied, as they're written, except
+// that a group in args is merged into the last group of the same name in
+// implied rather than being written again. That's the case for the groups
+// of a logger created by WithGroup, whose implied args are already nested
+// in them.
+func joinGroups(implied, args []any) []any {
+	args, stacktrace := splitStacktrace(args)
+
+	joined := make([]any, len(implied), len(implied)+len(args)+1)
+	copy(joined, implied)
+
+	for i := 0; i < len(args); {
+		key, val, next := nextArg(args, i)
+
+		if g, ok := asGroup(val); ok {
+			if j := lastGroup(joined, key); j >= 0 {
+				pg, _ := asGroup(joined[j])
+				joined[j] = Group(key, joinGroups(pg, g)...)
+
+				i = next
+				continue
+			}
+		}
+
+		joined = append(joined, args[i:next]...)
+		i = next
+	}
+
+	if stacktrace != "" {
+		joined = append(joined, stacktrace)
+	}
+
+	return joined
+}
+
+// lastGroup returns the index of the last group in args with the given name,
+// or -1 if there's none.
+func lastGroup(args []any, name string) int {
+	found := -1
+
+	for i := 0; i < len(args); {
+		key, val, next := nextArg(args, i)
+		if _, ok := asGroup(val); ok && key == name {
+			found = i
+		}
+
+		i = next
+	}
+
+	return found
+}
+
+// mergeArgs returns the key/value pairs and groups of implied followed by
+// those of args, sorted by key, as are the contents of the groups. Later
+// values replace earlier ones with the same key, except that groups with the
+// same name are merged.
+func mergeArgs(implied, args []any) []any {
+	result := make(map[string]any, len(implied)+len(args))
+	keys := make([]string, 0, len(implied)+len(args))
+
+	for _, args := range [][]any{implied, args} {
+		for i := 0; i < len(args); {
+			var (
+				key string
+				val any
+			)
+
+			key, val, i = nextArg(args, i)
+
+			prev, exists := result[key]
+			if !exists {
+				keys = append(keys, key)
+			}
+
+			if g, ok := asGroup(val); ok {
+				pg, _ := asGroup(prev)
+				val = Group(key, mergeArgs(pg, g)...)
+			}
+
+			result[key] = val
+		}
+	}
+
+	sort.Strings(keys)
+
+	merged := make([]any, 0, 2*len(result))
+	for _, k := range keys {
+		if f, ok := result[k].(Field); ok {
+			if _, ok := f.group(); ok {
+				merged = append(merged, f)
+				continue
+			}
+		}
+
+		merged = append(merged, k, result[k])
+	}
+
+	return merged
+}
+
+// asGroup returns the args of val if it's a group.
+func asGroup(val any) ([]any, bool) {
+	if f, ok := val.(Field); ok {
+		return f.group()
+	}
+
+	return nil, false
+}
+
+// appendPairs appends args to pairs as key/value pairs, flattening groups
+// into keys prefixed with the name of the group and a dot, and expanding
+// other fields into their key and value. A CapturedStacktrace value is
+// stored in stacktrace rather than appended.
+func appendPairs(pairs []any, prefix string, args []any, stacktrace *CapturedStacktrace) []any {
+	for i := 0; i < len(args); {
+		var (
+			key string
+			val any
+		)
+
+		key, val, i = nextArg(args, i)
+		val = resolveValue(val)
+
+		if f, ok := val.(Field); ok {
+			if group, ok := f.group(); ok {
+				pairs = appendPairs(pairs, prefix+key+".", group, stacktrace)
+				continue
+			}
+
+			val = f.Value()
+		}
+
+		// A stacktrace may also be passed as the value of a pair.
+		if cs, ok := val.(CapturedStacktrace); ok {
+			*stacktrace = cs
+			continue
+		}
+
+		pairs = append(pairs, prefix+key, val)
+	}
+
+	return pairs
+}
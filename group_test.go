@@ -0,0 +1,168 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroups(t *testing.T) {
+	t.Run("writes dotted keys in plain", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+		})
+
+		WithGroup(logger, "http").With("status", 200).Info("request", Group("req", "id", 1, "path", "/"))
+
+		assert.Equal(t, "[INFO]  request: http.status=200 http.req.id=1 http.req.path=/\n", buf.String())
+	})
+
+	t.Run("writes nested objects in JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		logger.Info("request", "a", 1, Group("req", "id", 1, "path", "/", Group("tls", "version", "1.3")))
+		WithGroup(logger, "http").With("status", 200).Info("request", "bytes", 10)
+		WithGroup(logger, "http").Info("request")
+
+		assert.Equal(t,
+			`{"@level":"info","@message":"request","a":1,"req":{"id":1,"path":"/","tls":{"version":"1.3"}}}`+"\n"+
+				`{"@level":"info","@message":"request","http":{"status":200,"bytes":10}}`+"\n"+
+				`{"@level":"info","@message":"request"}`+"\n",
+			buf.String())
+	})
+
+	t.Run("merges groups given to With", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		sub := logger.With("b", 2, Group("g", "y", 2)).With(Group("g", "x", 1), "a", 1)
+		sub = WithGroup(WithGroup(sub, "h").With("z", 3), "i").With("w", 4)
+		sub.Info("entry", "v", 5)
+
+		assert.Equal(t,
+			`{"@level":"info","@message":"entry","a":1,"b":2,"g":{"x":1,"y":2},"h":{"i":{"w":4,"v":5},"z":3}}`+"\n",
+			buf.String())
+	})
+
+	t.Run("omits empty groups", func(t *testing.T) {
+		var plain, js bytes.Buffer
+
+		New(&LoggerOptions{
+			Output:      &plain,
+			DisableTime: true,
+		}).Info("entry", Group("empty"), "a", 1)
+
+		New(&LoggerOptions{
+			Output:      &js,
+			JSONFormat:  true,
+			DisableTime: true,
+		}).Info("entry", Group("empty"), "a", 1)
+
+		assert.Equal(t, "[INFO]  entry: a=1\n", plain.String())
+		assert.Equal(t, `{"@level":"info","@message":"entry","a":1}`+"\n", js.String())
+	})
+
+	t.Run("writes dotted keys in logfmt", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:       &buf,
+			OutputFormat: OutputLogfmt,
+			DisableTime:  true,
+		})
+
+		WithGroup(logger, "http").Info("request", "status", 200, Group("req", "path", "/"))
+
+		assert.Equal(t, "level=info msg=request http.status=200 http.req.path=/\n", buf.String())
+	})
+
+	t.Run("redacts inside groups", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+			Redact:      &RedactOptions{},
+		})
+
+		logger.Info("login", Group("user", "name", "alice", "password", "hunter2"), Group("token", "id", 1))
+
+		assert.Equal(t, "[INFO]  login: user.name=alice user.password=[REDACTED] token=[REDACTED]\n", buf.String())
+	})
+
+	t.Run("nests args delivered to sinks", func(t *testing.T) {
+		var buf, sbuf bytes.Buffer
+
+		intercept := NewInterceptLogger(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		sink := NewSinkAdapter(&LoggerOptions{
+			Output:      &sbuf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		intercept.RegisterSink(sink)
+		defer intercept.DeregisterSink(sink)
+
+		WithGroup(intercept, "http").With("status", 200).Info("request", "bytes", 10)
+
+		want := `{"@level":"info","@message":"request","http":{"status":200,"bytes":10}}` + "\n"
+		assert.Equal(t, want, buf.String())
+		assert.Equal(t, want, sbuf.String())
+	})
+
+	t.Run("writes slog groups", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := FromSlogHandler(slog.NewJSONHandler(&buf, nil), nil)
+
+		WithGroup(logger, "http").With("status", 200).Info("request", Group("req", "id", 1))
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+
+		assert.Equal(t, map[string]any{
+			"status": float64(200),
+			"req":    map[string]any{"id": float64(1)},
+		}, raw["http"])
+	})
+
+	t.Run("leaves loggers without groups as they are", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		// The embedded Logger hides the WithGroup method of the logger.
+		logger := struct{ Logger }{New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+		})}
+
+		WithGroup(logger, "http").Info("request", "status", 200)
+
+		assert.Equal(t, "[INFO]  request: status=200\n", buf.String())
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclogtest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// eachPair calls fn with the key and value of each pair of args, as a Logger
+// writes them, until it returns false. A Field is its own key and value,
+// other args are a key followed by its value, and a trailing value without a
+// key is given hclog.MissingKey. A group is passed to fn with its args as
+// its value, followed by each of its pairs with the key prefixed by the name
+// of the group and a dot. It reports whether fn always returned true.
+func eachPair(args []any, prefix string, fn func(key string, val any, group bool) bool) bool {
+	for i := 0; i < len(args); i++ {
+		if f, ok := args[i].(hclog.Field); ok {
+			if members, ok := groupArgs(f); ok {
+				if !fn(prefix+f.Key, members, true) || !eachPair(members, prefix+f.Key+".", fn) {
+					return false
+				}
+			} else if !fn(prefix+f.Key, f.Value(), false) {
+				return false
+			}
+			continue
+		}
+
+		key, val := hclog.MissingKey, args[i]
+		if i+1 < len(args) {
+			key, val = argKey(args[i]), args[i+1]
+			i++
+		}
+
+		if !fn(prefix+key, val, false) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// groupArgs returns the args of a Field created by hclog.Group, and whether
+// it is one. A group's Value is its args, so it's told apart from a Field
+// created by hclog.Any with the same args by comparing it with the group
+// they make.
+func groupArgs(f hclog.Field) ([]any, bool) {
+	args, ok := f.Value().([]any)
+	if !ok {
+		return nil, false
+	}
+
+	return args, reflect.DeepEqual(f, hclog.Group(f.Key, args...))
+}
+
+// wrapGroups returns args nested in the given groups, outermost first, as a
+// sublogger created by hclog.WithGroup nests them. A trailing
+// hclog.CapturedStacktrace is left outside of the groups.
+func wrapGroups(groups []string, args []any) []any {
+	if len(groups) == 0 || len(args) == 0 {
+		return args
+	}
+
+	var trailing []any
+	if _, ok := args[len(args)-1].(hclog.CapturedStacktrace); ok && trailingValue(args) {
+		args, trailing = args[:len(args)-1], args[len(args)-1:]
+	}
+
+	if len(args) > 0 {
+		for i := len(groups) - 1; i >= 0; i-- {
+			args = []any{hclog.Group(groups[i], args...)}
+		}
+	}
+
+	return append(args[:len(args):len(args)], trailing...)
+}
+
+// trailingValue reports whether the last of args is a value without a key.
+func trailingValue(args []any) bool {
+	i := 0
+	for i < len(args)-1 {
+		if _, ok := args[i].(hclog.Field); ok {
+			i++
+		} else {
+			i += 2
+		}
+	}
+
+	return i == len(args)-1
+}
+
+func argKey(k any) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+
+	return fmt.Sprintf("%s", k)
+}
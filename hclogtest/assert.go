@@ -0,0 +1,63 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclogtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// AssertLogged fails t unless an entry which Matches the given level,
+// message and args has been recorded, and reports whether there is one.
+//
+//	logs := hclogtest.NewTB(t)
+//	...
+//	logs.AssertLogged(t, hclog.Warn, "retrying", "attempt", 2)
+func (l *Logger) AssertLogged(t testing.TB, level hclog.Level, msg string, args ...any) bool {
+	t.Helper()
+
+	entries := l.Entries()
+
+	for _, e := range entries {
+		if e.Matches(level, msg, args...) {
+			return true
+		}
+	}
+
+	t.Errorf("no entry matching [%s] %s %v was logged; entries:%s",
+		strings.ToUpper(level.String()), msg, args, formatEntries(entries))
+
+	return false
+}
+
+// AssertNotLogged fails t if an entry which Matches the given level, message
+// and args has been recorded, and reports whether there's none.
+func (l *Logger) AssertNotLogged(t testing.TB, level hclog.Level, msg string, args ...any) bool {
+	t.Helper()
+
+	for _, e := range l.Entries() {
+		if e.Matches(level, msg, args...) {
+			t.Errorf("unexpected entry was logged: %s", e)
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatEntries(entries []Entry) string {
+	if len(entries) == 0 {
+		return " none"
+	}
+
+	var sb strings.Builder
+	for _, e := range entries {
+		sb.WriteString("\n\t")
+		sb.WriteString(e.String())
+	}
+
+	return sb.String()
+}
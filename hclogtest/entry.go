@@ -0,0 +1,212 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclogtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Entry is an entry recorded by a Logger.
+type Entry struct {
+	Time    time.Time
+	Level   hclog.Level
+	Name    string
+	Message string
+
+	// Args are the args given with the entry, nested in the groups of the
+	// logger if it was created by WithGroup.
+	Args []any
+
+	// Implied are the args given to With.
+	Implied []any
+}
+
+// Value returns the value of key in the args of the entry, or in its
+// implied args if it's not in its args. The args of a group are found with
+// their keys prefixed by the name of the group and a dot, as the plain format
+// writes them, so "http.status" finds the status in the group "http", and
+// "http" finds the args of the group. If there's more than one, the last is
+// returned. The value of a Field is returned as given by its Value method,
+// and a LogValuer is resolved.
+func (e Entry) Value(key string) (any, bool) {
+	for _, args := range [][]any{e.Args, e.Implied} {
+		if val, ok := lookup(args, key); ok {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookup returns the value of the last arg in args with the given key.
+func lookup(args []any, key string) (val any, found bool) {
+	eachPair(args, "", func(k string, v any, _ bool) bool {
+		if k == key {
+			val, found = v, true
+		}
+		return true
+	})
+
+	if valuer, ok := val.(hclog.LogValuer); ok {
+		val = valuer.LogValue()
+	}
+
+	return val, found
+}
+
+// Matches reports whether the entry has the given level and message, and a
+// value equal to each of the given key/value pairs or Fields, as returned
+// by Value. A group matches if each of its args does, so that it needn't
+// have all of the args of the group in the entry. Values are compared with
+// reflect.DeepEqual, except that numbers are compared by value whatever
+// their type, so that 3 matches the int64 of hclog.Int("n", 3).
+func (e Entry) Matches(level hclog.Level, msg string, args ...any) bool {
+	if e.Level != level || e.Message != msg {
+		return false
+	}
+
+	return eachPair(args, "", func(key string, want any, group bool) bool {
+		if group {
+			return true
+		}
+
+		got, ok := e.Value(key)
+		return ok && equal(got, want)
+	})
+}
+
+// equal reports whether got and want are equal, as Matches compares them.
+func equal(got, want any) bool {
+	if reflect.DeepEqual(got, want) {
+		return true
+	}
+
+	g, w := reflect.ValueOf(got), reflect.ValueOf(want)
+
+	switch {
+	case isInt(g) && isInt(w):
+		return g.Int() == w.Int()
+	case isUint(g) && isUint(w):
+		return g.Uint() == w.Uint()
+	case isInt(g) && isUint(w):
+		return g.Int() >= 0 && uint64(g.Int()) == w.Uint()
+	case isUint(g) && isInt(w):
+		return w.Int() >= 0 && g.Uint() == uint64(w.Int())
+	case isNumber(g) && isNumber(w):
+		return toFloat(g) == toFloat(w)
+	default:
+		return false
+	}
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumber(v reflect.Value) bool {
+	return isInt(v) || isUint(v) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+// toFloat returns the value of a number as a float64.
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isInt(v):
+		return float64(v.Int())
+	case isUint(v):
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// String returns the entry in a form similar to the plain format, for
+// failure messages.
+func (e Entry) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "[%s] ", strings.ToUpper(e.Level.String()))
+	if e.Name != "" {
+		sb.WriteString(e.Name)
+		sb.WriteString(": ")
+	}
+
+	sb.WriteString(e.Message)
+
+	for _, args := range [][]any{e.Implied, e.Args} {
+		eachPair(args, "", func(key string, val any, group bool) bool {
+			if !group {
+				fmt.Fprintf(&sb, " %s=%v", key, val)
+			}
+			return true
+		})
+	}
+
+	return sb.String()
+}
+
+// Entries returns the entries recorded so far, oldest first.
+func (l *Logger) Entries() []Entry {
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+
+	entries := make([]Entry, len(l.rec.entries))
+	copy(entries, l.rec.entries)
+
+	return entries
+}
+
+// Filter returns the entries for which keep returns true, oldest first.
+func (l *Logger) Filter(keep func(Entry) bool) []Entry {
+	var entries []Entry
+
+	for _, e := range l.Entries() {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// EntriesAtLevel returns the entries at level or more severe, oldest first.
+func (l *Logger) EntriesAtLevel(level hclog.Level) []Entry {
+	return l.Filter(func(e Entry) bool {
+		return e.Level >= level
+	})
+}
+
+// EntriesNamed returns the entries of the logger with the given name and of
+// its subloggers, oldest first.
+func (l *Logger) EntriesNamed(name string) []Entry {
+	return l.Filter(func(e Entry) bool {
+		return e.Name == name || strings.HasPrefix(e.Name, name+".")
+	})
+}
+
+// Reset discards the entries recorded so far.
+func (l *Logger) Reset() {
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+
+	l.rec.entries = nil
+}
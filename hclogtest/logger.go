@@ -0,0 +1,303 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+// Package hclogtest provides an hclog.Logger which records entries in memory,
+// for tests to make assertions about what was logged.
+package hclogtest
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Make sure that Logger is an hclog.Logger
+var (
+	_ hclog.Logger      = &Logger{}
+	_ hclog.GroupLogger = &Logger{}
+)
+
+// Logger is an hclog.Logger which records each entry at or above its level.
+// Subloggers created by Named, With and so on record to the same Logger, and
+// share its level.
+type Logger struct {
+	rec *recorder
+
+	name    string
+	implied []any
+	groups  []string
+	level   *atomic.Int32
+}
+
+// recorder holds the entries recorded by a Logger and its subloggers.
+type recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+
+	// If tb is set, entries are also written with tb.Log by format.
+	tb     testing.TB
+	buf    bytes.Buffer
+	format hclog.Logger
+}
+
+// New returns a Logger which records entries at every level.
+func New() *Logger {
+	return newLogger(&recorder{})
+}
+
+// NewTB returns a Logger which records entries at every level, and also
+// writes them in the plain format with t.Log. They're then only shown if the
+// test fails or is run with -v, attributed to the line which logged them.
+func NewTB(t testing.TB) *Logger {
+	rec := &recorder{tb: t}
+	rec.format = hclog.New(&hclog.LoggerOptions{
+		Output:      &rec.buf,
+		Level:       hclog.Trace,
+		DisableTime: true,
+	})
+
+	return newLogger(rec)
+}
+
+func newLogger(rec *recorder) *Logger {
+	l := &Logger{
+		rec:   rec,
+		level: new(atomic.Int32),
+	}
+	l.level.Store(int32(hclog.Trace))
+
+	return l
+}
+
+// record adds e to the entries, and writes it if there's a testing.TB.
+func (r *recorder) record(e Entry) {
+	if r.tb != nil {
+		r.tb.Helper()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, e)
+
+	if r.tb == nil {
+		return
+	}
+
+	r.buf.Reset()
+
+	all := make([]any, 0, len(e.Implied)+len(e.Args))
+	all = append(all, e.Implied...)
+	all = append(all, e.Args...)
+
+	r.format.ResetNamed(e.Name).Log(e.Level, e.Message, all...)
+	r.tb.Log(strings.TrimRight(r.buf.String(), " \n"))
+}
+
+func (l *Logger) log(level hclog.Level, msg string, args []any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	if level < l.GetLevel() {
+		return
+	}
+
+	l.rec.record(Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Name:    l.name,
+		Message: msg,
+		Args:    wrapGroups(l.groups, args),
+		Implied: l.implied,
+	})
+}
+
+// Log records an entry at the given level.
+func (l *Logger) Log(level hclog.Level, msg string, args ...any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	l.log(level, msg, args)
+}
+
+// Trace records an entry at TRACE level.
+func (l *Logger) Trace(msg string, args ...any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	l.log(hclog.Trace, msg, args)
+}
+
+// Debug records an entry at DEBUG level.
+func (l *Logger) Debug(msg string, args ...any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	l.log(hclog.Debug, msg, args)
+}
+
+// Info records an entry at INFO level.
+func (l *Logger) Info(msg string, args ...any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	l.log(hclog.Info, msg, args)
+}
+
+// Warn records an entry at WARN level.
+func (l *Logger) Warn(msg string, args ...any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	l.log(hclog.Warn, msg, args)
+}
+
+// Error records an entry at ERROR level.
+func (l *Logger) Error(msg string, args ...any) {
+	if l.rec.tb != nil {
+		l.rec.tb.Helper()
+	}
+
+	l.log(hclog.Error, msg, args)
+}
+
+func (l *Logger) IsTrace() bool { return l.GetLevel() <= hclog.Trace }
+
+func (l *Logger) IsDebug() bool { return l.GetLevel() <= hclog.Debug }
+
+func (l *Logger) IsInfo() bool { return l.GetLevel() <= hclog.Info }
+
+func (l *Logger) IsWarn() bool { return l.GetLevel() <= hclog.Warn }
+
+func (l *Logger) IsError() bool { return l.GetLevel() <= hclog.Error }
+
+// ImpliedArgs returns the args given to With.
+func (l *Logger) ImpliedArgs() []any {
+	return l.implied
+}
+
+// With returns a sublogger which records the given args as implied args of
+// each entry, after those of l.
+func (l *Logger) With(args ...any) hclog.Logger {
+	sl := *l
+
+	args = wrapGroups(l.groups, args)
+	sl.implied = append(l.implied[:len(l.implied):len(l.implied)], args...)
+
+	return &sl
+}
+
+// WithGroup returns a sublogger which nests the args given to With and to
+// each entry in the named group.
+func (l *Logger) WithGroup(name string) hclog.Logger {
+	if name == "" {
+		return l
+	}
+
+	sl := *l
+	sl.groups = append(l.groups[:len(l.groups):len(l.groups)], name)
+
+	return &sl
+}
+
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Named returns a sublogger with name appended to the name of l.
+func (l *Logger) Named(name string) hclog.Logger {
+	sl := *l
+
+	if sl.name != "" {
+		sl.name = sl.name + "." + name
+	} else {
+		sl.name = name
+	}
+
+	return &sl
+}
+
+// ResetNamed returns a sublogger with the given name.
+func (l *Logger) ResetNamed(name string) hclog.Logger {
+	sl := *l
+	sl.name = name
+
+	return &sl
+}
+
+// SetLevel sets the level below which entries aren't recorded, for l and
+// all of its subloggers.
+func (l *Logger) SetLevel(level hclog.Level) {
+	if level == hclog.NoLevel {
+		level = hclog.DefaultLevel
+	}
+
+	l.level.Store(int32(level))
+}
+
+func (l *Logger) GetLevel() hclog.Level {
+	return hclog.Level(l.level.Load())
+}
+
+// StandardLogger returns a *log.Logger which records each line written to it
+// as an entry, as StandardWriter does.
+func (l *Logger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter returns a writer which records each line written to it as
+// an entry. The level is opts.ForceLevel if set, or else inferred from a
+// prefix such as "[DEBUG]" if opts.InferLevels is set, or else INFO.
+func (l *Logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+
+	return &stdWriter{logger: l, opts: *opts}
+}
+
+type stdWriter struct {
+	logger *Logger
+	opts   hclog.StandardLoggerOptions
+}
+
+func (w *stdWriter) Write(data []byte) (int, error) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		level := hclog.Info
+		switch {
+		case w.opts.ForceLevel != hclog.NoLevel:
+			level = w.opts.ForceLevel
+		case w.opts.InferLevels && strings.HasPrefix(line, "["):
+			if end := strings.IndexByte(line, ']'); end > 0 {
+				if lvl := hclog.LevelFromString(line[1:end]); lvl != hclog.NoLevel {
+					level = lvl
+					line = strings.TrimSpace(line[end+1:])
+				}
+			}
+		}
+
+		w.logger.log(level, line, nil)
+	}
+
+	return len(data), nil
+}
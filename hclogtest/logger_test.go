@@ -0,0 +1,187 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclogtest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTB records the messages given to Log and Errorf.
+type fakeTB struct {
+	testing.TB
+
+	logs   []string
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Log(args ...any) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("records entries", func(t *testing.T) {
+		logs := New()
+
+		var logger hclog.Logger = logs
+		sub := logger.Named("raft").With("peer", "a")
+
+		logger.Info("starting", "port", 8500)
+		sub.Warn("heartbeat failed", hclog.Err(errors.New("timeout")), "attempt", 2)
+
+		entries := logs.Entries()
+		require.Len(t, entries, 2)
+
+		assert.Equal(t, hclog.Info, entries[0].Level)
+		assert.Equal(t, "", entries[0].Name)
+		assert.Equal(t, "starting", entries[0].Message)
+		assert.Equal(t, []any{"port", 8500}, entries[0].Args)
+		assert.False(t, entries[0].Time.IsZero())
+
+		assert.Equal(t, "raft", entries[1].Name)
+		assert.Equal(t, []any{"peer", "a"}, entries[1].Implied)
+
+		val, ok := entries[1].Value("error")
+		assert.True(t, ok)
+		assert.EqualError(t, val.(error), "timeout")
+
+		assert.Equal(t, `[WARN] raft: heartbeat failed peer=a error=timeout attempt=2`, entries[1].String())
+	})
+
+	t.Run("asserts entries were logged", func(t *testing.T) {
+		ft := &fakeTB{}
+		logs := NewTB(ft)
+
+		logs.Named("raft").With("peer", "a").Warn("heartbeat failed", "attempt", 2, hclog.Int("term", 3))
+
+		assert.True(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed"))
+		assert.True(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed", "attempt", 2, "peer", "a"))
+		assert.True(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed", hclog.Int("term", 3)))
+		assert.True(t, logs.AssertNotLogged(ft, hclog.Error, "heartbeat failed"))
+
+		// Numbers match whatever their type.
+		assert.True(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed", "term", 3))
+		assert.True(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed", "attempt", uint8(2), "term", 3.0))
+		assert.Empty(t, ft.errors)
+
+		assert.False(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed", "attempt", 3))
+		assert.False(t, logs.AssertNotLogged(ft, hclog.Warn, "heartbeat failed", "attempt", 2))
+		assert.False(t, logs.AssertLogged(ft, hclog.Warn, "heartbeat failed", "term", "3"))
+
+		require.Len(t, ft.errors, 3)
+		assert.Equal(t,
+			"no entry matching [WARN] heartbeat failed [attempt 3] was logged; entries:\n"+
+				"\t[WARN] raft: heartbeat failed peer=a attempt=2 term=3",
+			ft.errors[0])
+		assert.Equal(t, "unexpected entry was logged: [WARN] raft: heartbeat failed peer=a attempt=2 term=3", ft.errors[1])
+
+		// Entries are asserted for subtests too.
+		t.Run("subtest", func(t *testing.T) {
+			logs.AssertLogged(t, hclog.Warn, "heartbeat failed", "term", 3)
+		})
+	})
+
+	t.Run("filters entries", func(t *testing.T) {
+		logs := New()
+
+		logs.Debug("a")
+		logs.Named("raft").Warn("b")
+		logs.Named("raft").Named("snapshot").Error("c")
+		logs.Named("raftish").Error("d")
+
+		messages := func(entries []Entry) []string {
+			var msgs []string
+			for _, e := range entries {
+				msgs = append(msgs, e.Message)
+			}
+			return msgs
+		}
+
+		assert.Equal(t, []string{"b", "c", "d"}, messages(logs.EntriesAtLevel(hclog.Warn)))
+		assert.Equal(t, []string{"b", "c"}, messages(logs.EntriesNamed("raft")))
+		assert.Equal(t, []string{"c"}, messages(logs.EntriesNamed("raft.snapshot")))
+
+		logs.Reset()
+		assert.Empty(t, logs.Entries())
+	})
+
+	t.Run("records only entries at its level", func(t *testing.T) {
+		logs := New()
+		sub := logs.Named("sub")
+
+		logs.SetLevel(hclog.Warn)
+		sub.Info("dropped")
+		sub.Warn("kept")
+
+		assert.False(t, sub.IsInfo())
+		assert.True(t, sub.IsWarn())
+		require.Len(t, logs.Entries(), 1)
+		assert.Equal(t, "kept", logs.Entries()[0].Message)
+	})
+
+	t.Run("nests args in groups", func(t *testing.T) {
+		logs := New()
+
+		logs.WithGroup("http").With("status", 200).Info("request", "bytes", 10)
+
+		e := logs.Entries()[0]
+		assert.Equal(t, []any{hclog.Group("http", "status", 200)}, e.Implied)
+		assert.Equal(t, []any{hclog.Group("http", "bytes", 10)}, e.Args)
+
+		val, ok := e.Value("http.status")
+		assert.True(t, ok)
+		assert.Equal(t, 200, val)
+
+		val, ok = e.Value("http")
+		assert.True(t, ok)
+		assert.Equal(t, []any{"bytes", 10}, val)
+
+		_, ok = e.Value("status")
+		assert.False(t, ok)
+
+		assert.Equal(t, "[INFO] request http.status=200 http.bytes=10", e.String())
+		assert.True(t, e.Matches(hclog.Info, "request", "http.status", 200, "http.bytes", 10))
+		assert.True(t, e.Matches(hclog.Info, "request", hclog.Group("http", "bytes", 10)))
+	})
+
+	t.Run("records lines from a standard logger", func(t *testing.T) {
+		logs := NewTB(t)
+
+		std := logs.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true})
+		std.Println("[DEBUG] connecting")
+		std.Println("no level")
+
+		logs.AssertLogged(t, hclog.Debug, "connecting")
+		logs.AssertLogged(t, hclog.Info, "no level")
+	})
+
+	t.Run("writes entries to a testing.TB", func(t *testing.T) {
+		ft := &fakeTB{}
+		logs := NewTB(ft)
+
+		logs.Named("raft").With("peer", "a").Info("elected", "term", 3)
+		logs.Error("multi", "out", "one\ntwo")
+
+		assert.Equal(t, []string{
+			"[INFO]  raft: elected: peer=a term=3",
+			"[ERROR] multi:\n  out=\n  | one\n  | two",
+		}, ft.logs)
+		assert.True(t, logs.AssertLogged(ft, hclog.Info, "elected", "term", 3))
+
+		// Entries appear in the output of a real test only if it fails.
+		logger := NewTB(t)
+		logger.Info("shown with -v", "attempt", 1)
+	})
+}
@@ -6,18 +6,63 @@ package hclog
 import (
 	"io"
 	"log"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
 
-var _ Logger = &interceptLogger{}
+var (
+	_ Logger                = &interceptLogger{}
+	_ SinkOptionsRegisterer = &interceptLogger{}
+)
 
 type interceptLogger struct {
 	Logger
 
 	mu        *sync.Mutex
-	sinkCount *int32
-	Sinks     map[SinkAdapter]struct{}
+	sinkLevel *atomic.Int32
+	Sinks     map[SinkAdapter]*sinkFilter
+	redactor  *redactor
+	groups    []string
+}
+
+// sinkFilter holds the options a sink was registered with.
+type sinkFilter struct {
+	level   Level
+	modules []string
+	filter  func(name string, level Level, msg string, args ...any) bool
+}
+
+func newSinkFilter(opts *SinkOptions) *sinkFilter {
+	if opts == nil {
+		opts = &SinkOptions{}
+	}
+
+	return &sinkFilter{
+		level:   opts.Level,
+		modules: append([]string(nil), opts.Modules...),
+		filter:  opts.Filter,
+	}
+}
+
+// wants reports whether the sink wants entries at level from the named
+// logger, before the args are known.
+func (f *sinkFilter) wants(name string, level Level) bool {
+	if level < f.level {
+		return false
+	}
+
+	if len(f.modules) == 0 {
+		return true
+	}
+
+	for _, m := range f.modules {
+		if name == m || strings.HasPrefix(name, m+".") {
+			return true
+		}
+	}
+
+	return false
 }
 
 func NewInterceptLogger(opts *LoggerOptions) InterceptLogger {
@@ -29,11 +74,12 @@ func NewInterceptLogger(opts *LoggerOptions) InterceptLogger {
 	intercept := &interceptLogger{
 		Logger:    l,
 		mu:        new(sync.Mutex),
-		sinkCount: new(int32),
-		Sinks:     make(map[SinkAdapter]struct{}),
+		sinkLevel: new(atomic.Int32),
+		Sinks:     make(map[SinkAdapter]*sinkFilter),
+		redactor:  l.redactor,
 	}
 
-	atomic.StoreInt32(intercept.sinkCount, 0)
+	intercept.sinkLevel.Store(int32(Off))
 
 	return intercept
 }
@@ -42,20 +88,54 @@ func (i *interceptLogger) Log(level Level, msg string, args ...any) {
 	i.log(level, msg, args...)
 }
 
+// LogFields emits the message and fields at the provided level. The fields
+// are only boxed once the entry is wanted by the logger or one of the sinks.
+func (i *interceptLogger) LogFields(level Level, msg string, fields ...Field) {
+	if level < i.GetLevel() && level < Level(i.sinkLevel.Load()) {
+		return
+	}
+
+	i.log(level, msg, fieldArgs(fields)...)
+}
+
 // log is used to make the caller stack frame lookup consistent. If Warn,Info,etc
 // all called Log then direct calls to Log would have a different stack frame
 // depth. By having all the methods call the same helper we ensure the stack
 // frame depth is the same.
 func (i *interceptLogger) log(level Level, msg string, args ...any) {
 	i.Logger.Log(level, msg, args...)
-	if atomic.LoadInt32(i.sinkCount) == 0 {
+	if level < Level(i.sinkLevel.Load()) {
 		return
 	}
 
 	i.mu.Lock()
 	defer i.mu.Unlock()
-	for s := range i.Sinks {
-		s.Accept(i.Name(), level, msg, i.retrieveImplied(args...)...)
+
+	name := i.Name()
+
+	// The args are redacted and grouped only once some sink wants the entry,
+	// but each sink is given its own copy of them along with the implied
+	// args, so that one may keep or modify them.
+	var grouped []any
+	var built bool
+
+	for s, f := range i.Sinks {
+		if !f.wants(name, level) {
+			continue
+		}
+
+		if !built {
+			grouped = wrapGroups(i.groups, i.redactor.redact(args))
+			built = true
+		}
+
+		all := i.retrieveImplied(grouped...)
+
+		if f.filter != nil && !f.filter(name, level, msg, all...) {
+			continue
+		}
+
+		s.Accept(name, level, msg, all...)
 	}
 }
 
@@ -87,6 +167,11 @@ func (i *interceptLogger) Error(msg string, args ...any) {
 func (i *interceptLogger) retrieveImplied(args ...any) []any {
 	top := i.ImpliedArgs()
 
+	if len(i.groups) > 0 {
+		// The groups of args are also in the implied args.
+		return joinGroups(top, args)
+	}
+
 	cp := make([]any, len(top)+len(args))
 	copy(cp, top)
 	copy(cp[len(top):], args)
@@ -139,14 +224,36 @@ func (i *interceptLogger) With(args ...any) Logger {
 	return &sub
 }
 
+// Return a sub-Logger which nests the key/value pairs given to With and to
+// each entry under the named group. Registered sinks will receive them
+// nested in the same way.
+func (i *interceptLogger) WithGroup(name string) Logger {
+	if name == "" {
+		return i
+	}
+
+	var sub = *i
+
+	sub.Logger = WithGroup(i.Logger, name)
+	sub.groups = append(i.groups[:len(i.groups):len(i.groups)], name)
+
+	return &sub
+}
+
 // RegisterSink attaches a SinkAdapter to interceptLoggers sinks.
 func (i *interceptLogger) RegisterSink(sink SinkAdapter) {
+	i.RegisterSinkWithOptions(sink, nil)
+}
+
+// RegisterSinkWithOptions attaches a SinkAdapter to interceptLoggers sinks,
+// which only receives the entries selected by opts.
+func (i *interceptLogger) RegisterSinkWithOptions(sink SinkAdapter, opts *SinkOptions) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	i.Sinks[sink] = struct{}{}
+	i.Sinks[sink] = newSinkFilter(opts)
 
-	atomic.AddInt32(i.sinkCount, 1)
+	i.updateSinkLevel()
 }
 
 // DeregisterSink removes a SinkAdapter from interceptLoggers sinks.
@@ -156,7 +263,19 @@ func (i *interceptLogger) DeregisterSink(sink SinkAdapter) {
 
 	delete(i.Sinks, sink)
 
-	atomic.AddInt32(i.sinkCount, -1)
+	i.updateSinkLevel()
+}
+
+// updateSinkLevel stores the most verbose level wanted by any sink, so that
+// log can skip entries no sink wants without taking the mutex. The mutex
+// must be held by the caller.
+func (i *interceptLogger) updateSinkLevel() {
+	level := Off
+	for _, f := range i.Sinks {
+		level = min(level, f.level)
+	}
+
+	i.sinkLevel.Store(int32(level))
 }
 
 func (i *interceptLogger) StandardLoggerIntercept(opts *StandardLoggerOptions) *log.Logger {
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
 	"testing"
@@ -369,3 +370,145 @@ func TestInterceptLogger(t *testing.T) {
 		assert.Equal(t, "[INFO]  this is another test: production=\"13 beans/day\"\n", rest)
 	})
 }
+
+// recordingSink records the entries given to Accept.
+type recordingSink struct {
+	entries []string
+}
+
+func (s *recordingSink) Accept(name string, level Level, msg string, args ...any) {
+	s.entries = append(s.entries, fmt.Sprintf("%s %s %s %v", level, name, msg, args))
+}
+
+func TestInterceptLoggerSinkOptions(t *testing.T) {
+	t.Run("sends sinks only the entries at their level", func(t *testing.T) {
+		intercept := NewInterceptLogger(&LoggerOptions{Output: io.Discard})
+		il := intercept.(*interceptLogger)
+
+		assert.Equal(t, Off, Level(il.sinkLevel.Load()))
+
+		warn, debug := &recordingSink{}, &recordingSink{}
+		intercept.(SinkOptionsRegisterer).RegisterSinkWithOptions(warn, &SinkOptions{Level: Warn})
+		assert.Equal(t, Warn, Level(il.sinkLevel.Load()))
+
+		intercept.(SinkOptionsRegisterer).RegisterSinkWithOptions(debug, &SinkOptions{Level: Debug})
+		assert.Equal(t, Debug, Level(il.sinkLevel.Load()))
+
+		intercept.Trace("a")
+		intercept.Debug("b")
+		intercept.Error("c", "n", 1)
+
+		assert.Equal(t, []string{"error  c [n 1]"}, warn.entries)
+		assert.Equal(t, []string{"debug  b []", "error  c [n 1]"}, debug.entries)
+
+		intercept.DeregisterSink(debug)
+		assert.Equal(t, Warn, Level(il.sinkLevel.Load()))
+
+		intercept.DeregisterSink(debug)
+		intercept.DeregisterSink(warn)
+		assert.Equal(t, Off, Level(il.sinkLevel.Load()))
+	})
+
+	t.Run("sends sinks only the entries of their modules", func(t *testing.T) {
+		intercept := NewInterceptLogger(&LoggerOptions{Output: io.Discard})
+
+		sink := &recordingSink{}
+		intercept.(SinkOptionsRegisterer).RegisterSinkWithOptions(sink, &SinkOptions{Modules: []string{"raft", "http.server"}})
+		defer intercept.DeregisterSink(sink)
+
+		intercept.Info("root")
+		intercept.Named("raft").Info("a")
+		intercept.Named("raft").Named("snapshot").Info("b")
+		intercept.Named("raftish").Info("c")
+		intercept.Named("http").Info("d")
+		intercept.Named("http").Named("server").Info("e")
+
+		assert.Equal(t, []string{
+			"info raft a []",
+			"info raft.snapshot b []",
+			"info http.server e []",
+		}, sink.entries)
+	})
+
+	t.Run("sends sinks only the entries their filter accepts", func(t *testing.T) {
+		intercept := NewInterceptLogger(&LoggerOptions{Output: io.Discard})
+
+		var calls int
+
+		sink := &recordingSink{}
+		intercept.(SinkOptionsRegisterer).RegisterSinkWithOptions(sink, &SinkOptions{
+			Level: Info,
+			Filter: func(name string, level Level, msg string, args ...any) bool {
+				calls++
+				for i := 0; i+1 < len(args); i += 2 {
+					if args[i] == "audit" {
+						return args[i+1] == true
+					}
+				}
+				return false
+			},
+		})
+		defer intercept.DeregisterSink(sink)
+
+		audit := intercept.With("audit", true)
+
+		intercept.Debug("below the level", "audit", true)
+		intercept.Info("not audited")
+		audit.Info("audited", "user", "a")
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, []string{"info  audited [audit true user a]"}, sink.entries)
+	})
+
+	t.Run("replaces the options of a sink registered again", func(t *testing.T) {
+		intercept := NewInterceptLogger(&LoggerOptions{Output: io.Discard})
+
+		sink := &recordingSink{}
+		intercept.(SinkOptionsRegisterer).RegisterSinkWithOptions(sink, &SinkOptions{Level: Error})
+		intercept.RegisterSink(sink)
+		defer intercept.DeregisterSink(sink)
+
+		intercept.Trace("a")
+
+		assert.Equal(t, []string{"trace  a []"}, sink.entries)
+		assert.Equal(t, NoLevel, Level(intercept.(*interceptLogger).sinkLevel.Load()))
+	})
+
+	t.Run("sends sinks without a level the entries at every level", func(t *testing.T) {
+		intercept := NewInterceptLogger(&LoggerOptions{Output: io.Discard})
+
+		sink := &recordingSink{}
+		intercept.RegisterSink(sink)
+		defer intercept.DeregisterSink(sink)
+
+		intercept.Log(NoLevel, "a")
+		intercept.Trace("b")
+
+		assert.Equal(t, []string{"none  a []", "trace  b []"}, sink.entries)
+	})
+
+	t.Run("gives each sink its own args", func(t *testing.T) {
+		intercept := NewInterceptLogger(&LoggerOptions{Output: io.Discard})
+
+		first, second := &mutatingSink{}, &recordingSink{}
+		intercept.RegisterSink(first)
+		defer intercept.DeregisterSink(first)
+
+		intercept.RegisterSink(second)
+		defer intercept.DeregisterSink(second)
+
+		intercept.With("a", 1).Info("entry", "b", 2)
+		intercept.With("a", 1).Info("entry", "b", 2)
+
+		assert.Equal(t, []string{"info  entry [a 1 b 2]", "info  entry [a 1 b 2]"}, second.entries)
+	})
+}
+
+// mutatingSink overwrites the args it's given.
+type mutatingSink struct{}
+
+func (s *mutatingSink) Accept(name string, level Level, msg string, args ...any) {
+	for i := range args {
+		args[i] = "mutated"
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"log"
 	"reflect"
 	"runtime"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,6 +22,8 @@ import (
 	"unicode/utf8"
 
 	"github.com/fatih/color"
+
+	"github.com/hashicorp/go-hclog/internal/callerpath"
 )
 
 // TimeFormat is the time format to use for plain (non-JSON) output.
@@ -77,11 +78,18 @@ func init() {
 // Make sure that intLogger is a Logger
 var _ Logger = &intLogger{}
 
+// Make sure that intLogger is a Flushable
+var _ Flushable = &intLogger{}
+
 // intLogger is an internal logger implementation. Internal in that it is
 // defined entirely by this package.
 type intLogger struct {
-	json              bool
+	format            OutputFormat
 	jsonEscapeEnabled bool
+	jsonFields        JSONFields
+	redactor          *redactor
+	groups            []string
+	structuredErrors  bool
 	callerOffset      int
 	name              string
 	timeFormat        string
@@ -107,13 +115,39 @@ type intLogger struct {
 	// The logger this one was created from. Only set when syncParentLevel is set
 	parent *intLogger
 
+	// Optional per-name levels, along with the name of the root logger they
+	// are matched relative to and a cache of the result for this logger.
+	levelDirectives *LevelDirectives
+	directiveRoot   string
+	directiveCache  *atomic.Uint64
+
 	headerColor ColorOption
 	fieldColor  ColorOption
 
+	// Set when the format is OutputSyslog
+	syslog *syslogFormat
+
+	// Set when the format is OutputGELF
+	gelf *gelfFormat
+
+	// Set when the format is OutputOTLP
+	otlp *otlpFormat
+
+	// Set when the format is OutputECS
+	ecs *ecsFormat
+
 	implied []any
 
 	exclude func(level Level, msg string, args ...any) bool
 
+	// Shared amongst all the loggers created in this hierarchy, nil unless
+	// sampling is enabled
+	sampler *sampler
+
+	// Shared amongst all the loggers created in this hierarchy, nil unless
+	// repeated entries are collapsed
+	repeats *repeatCollapser
+
 	// create subloggers with their own level setting
 	independentLevels bool
 	syncParentLevel   bool
@@ -172,9 +206,17 @@ func newLogger(opts *LoggerOptions) *intLogger {
 		primaryColor = opts.Color
 	}
 
+	format := opts.OutputFormat
+	if format == OutputPlain && opts.JSONFormat {
+		format = OutputJSON
+	}
+
 	l := &intLogger{
-		json:              opts.JSONFormat,
+		format:            format,
 		jsonEscapeEnabled: !opts.JSONEscapeDisabled,
+		jsonFields:        resolveJSONFields(opts.JSONFields),
+		redactor:          newRedactor(opts.Redact),
+		structuredErrors:  opts.StructuredErrors,
 		name:              opts.Name,
 		timeFormat:        TimeFormat,
 		timeFn:            time.Now,
@@ -189,13 +231,26 @@ func newLogger(opts *LoggerOptions) *intLogger {
 		headerColor:       headerColor,
 		fieldColor:        fieldColor,
 		subloggerHook:     opts.SubloggerHook,
+		levelDirectives:   opts.LevelDirectives,
+		directiveRoot:     opts.Name,
+		directiveCache:    new(atomic.Uint64),
 	}
 	if opts.IncludeLocation {
 		l.callerOffset = offsetIntLogger + opts.AdditionalLocationOffset
 	}
 
-	if l.json {
+	switch l.format {
+	case OutputJSON, OutputLogfmt:
 		l.timeFormat = TimeFormatJSON
+	case OutputECS:
+		l.timeFormat = TimeFormatJSON
+		l.ecs = newECSFormat(opts.ECS)
+	case OutputSyslog:
+		l.syslog = newSyslogFormat(opts.Syslog)
+	case OutputGELF:
+		l.gelf = newGELFFormat(opts.GELF)
+	case OutputOTLP:
+		l.otlp = newOTLPFormat(opts.OTLP)
 	}
 	if opts.TimeFn != nil {
 		l.timeFn = opts.TimeFn
@@ -208,6 +263,14 @@ func newLogger(opts *LoggerOptions) *intLogger {
 		l.subloggerHook = identityHook
 	}
 
+	if opts.Sampling != nil {
+		l.sampler = newSampler(opts.Sampling, l.logSampled)
+	}
+
+	if opts.CollapseRepeats > 0 {
+		l.repeats = newRepeatCollapser(opts.CollapseRepeats, mutex)
+	}
+
 	l.setColorization(opts)
 
 	atomic.StoreInt32(l.level, int32(level))
@@ -230,51 +293,108 @@ func (l *intLogger) log(name string, level Level, msg string, args ...any) {
 		return
 	}
 
-	t := l.timeFn()
+	var pc uintptr
+	if l.callerOffset > 0 {
+		var pcs [1]uintptr
+		if runtime.Callers(l.callerOffset, pcs[:]) > 0 {
+			pc = pcs[0]
+		}
+	}
+
+	l.emit(l.timeFn(), pc, name, level, msg, args...)
+}
+
+// emit writes a single entry that has already passed the level check. The
+// time and caller program counter are provided by the caller so that entries
+// originating elsewhere, such as from a slog.Record, retain their own values.
+// A zero time omits the timestamp and a zero pc omits the caller location.
+func (l *intLogger) emit(t time.Time, pc uintptr, name string, level Level, msg string, args ...any) {
+	if l.exclude != nil && l.excluded(level, msg, args) {
+		return
+	}
+
+	if l.sampler != nil && !l.sampler.sample(name, level, msg) {
+		return
+	}
+
+	args = wrapGroups(l.groups, l.redactor.redact(args))
+
+	// LogValuers are resolved before taking the mutex, so that one may log
+	// through this logger, and a slow one doesn't block other entries.
+	args, _ = resolveArgs(args)
+	implied, _ := resolveArgs(l.implied)
 
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	if l.exclude != nil && l.exclude(level, msg, args...) {
+	if l.repeats != nil && l.repeats.hold(l, t, pc, name, level, msg, implied, args) {
 		return
 	}
 
-	if l.json {
-		l.logJSON(t, name, level, msg, args...)
-	} else {
-		l.logPlain(t, name, level, msg, args...)
+	l.write(t, pc, name, level, msg, implied, args)
+}
+
+// excluded reports whether Exclude excludes the entry. Exclude is given the
+// args as they were logged, and is called with the mutex held, so that calls
+// to it are never concurrent.
+func (l *intLogger) excluded(level Level, msg string, args []any) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.exclude(level, msg, args...)
+}
+
+// write formats the entry and flushes it to the output. The mutex must be
+// held by the caller.
+func (l *intLogger) write(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	switch l.format {
+	case OutputJSON:
+		l.logJSON(t, pc, name, level, msg, implied, args)
+	case OutputSyslog:
+		l.logSyslog(t, pc, name, level, msg, implied, args)
+	case OutputLogfmt:
+		l.logLogfmt(t, pc, name, level, msg, implied, args)
+	case OutputGELF:
+		l.logGELF(t, pc, name, level, msg, implied, args)
+	case OutputOTLP:
+		l.logOTLP(t, pc, name, level, msg, implied, args)
+	case OutputECS:
+		l.logECS(t, pc, name, level, msg, implied, args)
+	default:
+		l.logPlain(t, pc, name, level, msg, implied, args)
 	}
 
 	_ = l.writer.Flush(level)
 }
 
-// Cleanup a path by returning the last 2 segments of the path only.
-func trimCallerPath(path string) string {
-	// lovely borrowed from zap
-	// nb. To make sure we trim the path correctly on Windows too, we
-	// counter-intuitively need to use '/' and *not* os.PathSeparator here,
-	// because the path given originates from Go stdlib, specifically
-	// runtime.Caller() which (as of Mar/17) returns forward slashes even on
-	// Windows.
-	//
-	// See https://github.com/golang/go/issues/3335
-	// and https://github.com/golang/go/issues/18151
-	//
-	// for discussion on the issue on Go side.
+// logSampled writes a summary entry for each group of entries dropped by the
+// sampler during its last window.
+func (l *intLogger) logSampled(summaries []samplerSummary) {
+	t := l.timeFn()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	implied, _ := resolveArgs(l.implied)
+
+	for _, s := range summaries {
+		l.write(t, 0, s.name, s.level, SampledMessage, implied, []any{"message", s.msg, "dropped", s.dropped})
+	}
+}
 
-	// Find the last separator.
-	idx := strings.LastIndexByte(path, '/')
-	if idx == -1 {
-		return path
+// callerLocation returns the file and line for the program counter of a
+// logging call, as captured by runtime.Callers.
+func callerLocation(pc uintptr) (file string, line int, ok bool) {
+	if pc == 0 {
+		return "", 0, false
 	}
 
-	// Find the penultimate separator.
-	idx = strings.LastIndexByte(path[:idx], '/')
-	if idx == -1 {
-		return path
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return "", 0, false
 	}
 
-	return path[idx+1:]
+	return frame.File, frame.Line, true
 }
 
 // isNormal indicates if the rune is one allowed to exist as an unquoted
@@ -306,9 +426,9 @@ func needsQuoting(str string) bool {
 //  2. Color the whole log line, based on the level.
 //  3. Color only the header (level) part of the log line.
 //  4. Color both the header and fields of the log line.
-func (l *intLogger) logPlain(t time.Time, name string, level Level, msg string, args ...any) {
+func (l *intLogger) logPlain(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
 
-	if !l.disableTime {
+	if !l.disableTime && !t.IsZero() {
 		_, _ = l.writer.WriteString(t.Format(l.timeFormat))
 		_ = l.writer.WriteByte(' ')
 	}
@@ -326,9 +446,9 @@ func (l *intLogger) logPlain(t time.Time, name string, level Level, msg string,
 	}
 
 	if l.callerOffset > 0 {
-		if _, file, line, ok := runtime.Caller(l.callerOffset); ok {
+		if file, line, ok := callerLocation(pc); ok {
 			_ = l.writer.WriteByte(' ')
-			_, _ = l.writer.WriteString(trimCallerPath(file))
+			_, _ = l.writer.WriteString(callerpath.Trim(file))
 			_ = l.writer.WriteByte(':')
 			_, _ = l.writer.WriteString(strconv.Itoa(line))
 			_ = l.writer.WriteByte(':')
@@ -347,147 +467,198 @@ func (l *intLogger) logPlain(t time.Time, name string, level Level, msg string,
 		_, _ = l.writer.WriteString(msg)
 	}
 
-	args = append(l.implied, args...)
+	args = append(implied[:len(implied):len(implied)], args...)
 
 	var stacktrace CapturedStacktrace
 
 	if len(args) > 0 {
-		if len(args)%2 != 0 {
-			cs, ok := args[len(args)-1].(CapturedStacktrace)
-			if ok {
-				args = args[:len(args)-1]
-				stacktrace = cs
-			} else {
-				extra := args[len(args)-1]
-				args = append(args[:len(args)-1], MissingKey, extra)
-			}
-		}
+		args, stacktrace = splitStacktrace(args)
 
 		_ = l.writer.WriteByte(':')
 
-		// Handle the field arguments, which come in pairs (key=val).
-	FOR:
-		for i := 0; i < len(args); i = i + 2 {
-			var (
-				key string
-				val string
-				raw bool
-			)
-
-			// Convert the field value to a string.
-			switch st := args[i+1].(type) {
-			case string:
-				val = st
-				if st == "" {
-					val = `""`
-					raw = true
-				}
-			case int:
-				val = strconv.FormatInt(int64(st), 10)
-			case int64:
-				val = strconv.FormatInt(int64(st), 10)
-			case int32:
-				val = strconv.FormatInt(int64(st), 10)
-			case int16:
-				val = strconv.FormatInt(int64(st), 10)
-			case int8:
-				val = strconv.FormatInt(int64(st), 10)
-			case uint:
-				val = strconv.FormatUint(uint64(st), 10)
-			case uint64:
-				val = strconv.FormatUint(uint64(st), 10)
-			case uint32:
-				val = strconv.FormatUint(uint64(st), 10)
-			case uint16:
-				val = strconv.FormatUint(uint64(st), 10)
-			case uint8:
-				val = strconv.FormatUint(uint64(st), 10)
-			case Hex:
-				val = "0x" + strconv.FormatUint(uint64(st), 16)
-			case Octal:
-				val = "0" + strconv.FormatUint(uint64(st), 8)
-			case Binary:
-				val = "0b" + strconv.FormatUint(uint64(st), 2)
-			case CapturedStacktrace:
-				stacktrace = st
-				continue FOR
-			case Format:
-				val = fmt.Sprintf(st[0].(string), st[1:]...)
-			case Quote:
-				raw = true
-				val = strconv.Quote(string(st))
-			default:
-				v := reflect.ValueOf(st)
-				if v.Kind() == reflect.Slice {
-					val = l.renderSlice(v)
-					raw = true
-				} else {
-					val = fmt.Sprintf("%v", st)
-				}
-			}
+		l.writePlainArgs("", args, &stacktrace)
+	}
 
-			// Convert the field key to a string.
-			switch st := args[i].(type) {
-			case string:
-				key = st
-			default:
-				key = fmt.Sprintf("%s", st)
-			}
+	_, _ = l.writer.WriteString("\n")
+
+	if stacktrace != "" {
+		_, _ = l.writer.WriteString(string(stacktrace))
+		_, _ = l.writer.WriteString("\n")
+	}
+}
+
+// writePlainArgs writes the key/value pairs and fields of args, prefixing
+// their keys with prefix. The args of a group are written with its name and
+// a dot added to the prefix.
+func (l *intLogger) writePlainArgs(prefix string, args []any, stacktrace *CapturedStacktrace) {
+	// Handle the field arguments, which come in pairs (key=val) or as
+	// a Field.
+	for i := 0; i < len(args); {
+		var (
+			key string
+			val string
+			raw bool
+			v   any
+		)
+
+		key, v, i = nextArg(args, i)
+
+		// Convert the field value to a string.
+		if st, ok := v.(CapturedStacktrace); ok {
+			*stacktrace = st
+			continue
+		}
+
+		f, isField := v.(Field)
+		if group, ok := f.group(); ok {
+			l.writePlainArgs(prefix+key+".", group, stacktrace)
+			continue
+		}
+
+		key = prefix + key
 
-			// Optionally apply the ANSI "faint" and "bold"
-			// SGR values to the key.
+		// Numeric field values never need quoting, so they're written
+		// without first being rendered to a string.
+		numeric := isField && f.numeric()
+		if !numeric {
+			val, raw = l.plainValue(v)
+		}
+
+		// Optionally apply the ANSI "faint" and "bold"
+		// SGR values to the key.
+		if l.fieldColor != ColorOff {
+			key = faintBoldColor.Sprint(key)
+		}
+
+		// Values may contain multiple lines, and that format
+		// is preserved, with each line prefixed with a "  | "
+		// to show it's part of a collection of lines.
+		//
+		// Values may also need quoting, if not all the runes
+		// in the value string are "normal", like if they
+		// contain ANSI escape sequences.
+		if strings.Contains(val, "\n") {
+			_, _ = l.writer.WriteString("\n  ")
+			_, _ = l.writer.WriteString(key)
 			if l.fieldColor != ColorOff {
-				key = faintBoldColor.Sprint(key)
+				_, _ = l.writer.WriteString(faintFieldSeparatorWithNewLine)
+				writeIndent(l.writer, val, faintMultiLinePrefix)
+			} else {
+				_, _ = l.writer.WriteString("=\n")
+				writeIndent(l.writer, val, "  | ")
 			}
-
-			// Values may contain multiple lines, and that format
-			// is preserved, with each line prefixed with a "  | "
-			// to show it's part of a collection of lines.
-			//
-			// Values may also need quoting, if not all the runes
-			// in the value string are "normal", like if they
-			// contain ANSI escape sequences.
-			if strings.Contains(val, "\n") {
-				_, _ = l.writer.WriteString("\n  ")
-				_, _ = l.writer.WriteString(key)
-				if l.fieldColor != ColorOff {
-					_, _ = l.writer.WriteString(faintFieldSeparatorWithNewLine)
-					writeIndent(l.writer, val, faintMultiLinePrefix)
-				} else {
-					_, _ = l.writer.WriteString("=\n")
-					writeIndent(l.writer, val, "  | ")
-				}
-				_, _ = l.writer.WriteString("  ")
-			} else if !raw && needsQuoting(val) {
-				_ = l.writer.WriteByte(' ')
-				_, _ = l.writer.WriteString(key)
-				if l.fieldColor != ColorOff {
-					_, _ = l.writer.WriteString(faintFieldSeparator)
-				} else {
-					_ = l.writer.WriteByte('=')
-				}
-				_ = l.writer.WriteByte('"')
-				writeEscapedForOutput(l.writer, val, true)
-				_ = l.writer.WriteByte('"')
+			_, _ = l.writer.WriteString("  ")
+		} else if !raw && needsQuoting(val) {
+			_ = l.writer.WriteByte(' ')
+			_, _ = l.writer.WriteString(key)
+			if l.fieldColor != ColorOff {
+				_, _ = l.writer.WriteString(faintFieldSeparator)
+			} else {
+				_ = l.writer.WriteByte('=')
+			}
+			_ = l.writer.WriteByte('"')
+			writeEscapedForOutput(l.writer, val, true)
+			_ = l.writer.WriteByte('"')
+		} else {
+			_ = l.writer.WriteByte(' ')
+			_, _ = l.writer.WriteString(key)
+			if l.fieldColor != ColorOff {
+				_, _ = l.writer.WriteString(faintFieldSeparator)
+			} else {
+				_ = l.writer.WriteByte('=')
+			}
+			if numeric {
+				_, _ = l.writer.Write(f.appendNumber(l.writer.b.AvailableBuffer()))
 			} else {
-				_ = l.writer.WriteByte(' ')
-				_, _ = l.writer.WriteString(key)
-				if l.fieldColor != ColorOff {
-					_, _ = l.writer.WriteString(faintFieldSeparator)
-				} else {
-					_ = l.writer.WriteByte('=')
-				}
 				_, _ = l.writer.WriteString(val)
 			}
 		}
 	}
+}
 
-	_, _ = l.writer.WriteString("\n")
+// plainValue renders an argument value as it appears in the plain format.
+// raw reports whether the value is already quoted or otherwise must not be
+// quoted again.
+func (l *intLogger) plainValue(v any) (val string, raw bool) {
+	switch st := v.(type) {
+	case Field:
+		return l.plainFieldValue(st)
+	case string:
+		val = st
+		if st == "" {
+			val = `""`
+			raw = true
+		}
+	case int:
+		val = strconv.FormatInt(int64(st), 10)
+	case int64:
+		val = strconv.FormatInt(int64(st), 10)
+	case int32:
+		val = strconv.FormatInt(int64(st), 10)
+	case int16:
+		val = strconv.FormatInt(int64(st), 10)
+	case int8:
+		val = strconv.FormatInt(int64(st), 10)
+	case uint:
+		val = strconv.FormatUint(uint64(st), 10)
+	case uint64:
+		val = strconv.FormatUint(uint64(st), 10)
+	case uint32:
+		val = strconv.FormatUint(uint64(st), 10)
+	case uint16:
+		val = strconv.FormatUint(uint64(st), 10)
+	case uint8:
+		val = strconv.FormatUint(uint64(st), 10)
+	case Hex:
+		val = "0x" + strconv.FormatUint(uint64(st), 16)
+	case Octal:
+		val = "0" + strconv.FormatUint(uint64(st), 8)
+	case Binary:
+		val = "0b" + strconv.FormatUint(uint64(st), 2)
+	case Format:
+		val = fmt.Sprintf(st[0].(string), st[1:]...)
+	case Quote:
+		raw = true
+		val = strconv.Quote(string(st))
+	default:
+		if err, ok := st.(error); ok && l.structuredErrors {
+			val = errorText(err)
+			break
+		}
 
-	if stacktrace != "" {
-		_, _ = l.writer.WriteString(string(stacktrace))
-		_, _ = l.writer.WriteString("\n")
+		rv := reflect.ValueOf(st)
+		if rv.Kind() == reflect.Slice {
+			val = l.renderSlice(rv)
+			raw = true
+		} else {
+			val = fmt.Sprintf("%v", st)
+		}
+	}
+
+	return val, raw
+}
+
+// splitArgs joins the implied args and args into key/value pairs, expanding
+// any Field into its key and value and flattening groups into dotted keys,
+// giving a trailing value without a key its MissingKey and separating out any
+// CapturedStacktrace.
+func splitArgs(implied, args []any) (pairs []any, stacktrace CapturedStacktrace) {
+	pairs = make([]any, 0, len(implied)+2*len(args))
+
+	args, stacktrace = splitStacktrace(args)
+
+	pairs = appendPairs(pairs, "", implied, &stacktrace)
+	pairs = appendPairs(pairs, "", args, &stacktrace)
+
+	return pairs, stacktrace
+}
+
+// argKey renders an argument key as a string.
+func argKey(k any) string {
+	if s, ok := k.(string); ok {
+		return s
 	}
+	return fmt.Sprintf("%s", k)
 }
 
 func writeIndent(w *writer, str string, indent string) {
@@ -624,101 +795,73 @@ func (l *intLogger) renderSlice(v reflect.Value) string {
 	return buf.String()
 }
 
-// JSON logging function
-func (l *intLogger) logJSON(t time.Time, name string, level Level, msg string, args ...any) {
-	vals := l.jsonMapEntry(t, name, level, msg)
-	args = append(l.implied, args...)
-
-	if len(args) > 0 {
-		if len(args)%2 != 0 {
-			cs, ok := args[len(args)-1].(CapturedStacktrace)
-			if ok {
-				args = args[:len(args)-1]
-				vals["stacktrace"] = cs
-			} else {
-				extra := args[len(args)-1]
-				args = append(args[:len(args)-1], MissingKey, extra)
-			}
+// jsonValue converts an arg value to the value to be marshaled in its place.
+func jsonValue(val any) any {
+	switch sv := val.(type) {
+	case error:
+		// Check if val is of type error. If error type doesn't
+		// implement json.Marshaler or encoding.TextMarshaler
+		// then set val to err.Error() so that it gets marshaled
+		switch sv.(type) {
+		case json.Marshaler, encoding.TextMarshaler:
+		default:
+			val = sv.Error()
 		}
+	case Format:
+		val = fmt.Sprintf(sv[0].(string), sv[1:]...)
+	}
 
-		for i := 0; i < len(args); i = i + 2 {
-			val := args[i+1]
-			switch sv := val.(type) {
-			case error:
-				// Check if val is of type error. If error type doesn't
-				// implement json.Marshaler or encoding.TextMarshaler
-				// then set val to err.Error() so that it gets marshaled
-				switch sv.(type) {
-				case json.Marshaler, encoding.TextMarshaler:
-				default:
-					val = sv.Error()
-				}
-			case Format:
-				val = fmt.Sprintf(sv[0].(string), sv[1:]...)
-			}
+	return val
+}
 
-			var key string
+// resolveJSONFields returns the field names to use, with defaults filled in
+// and dropped fields set to "".
+func resolveJSONFields(opts *JSONFields) JSONFields {
+	var f JSONFields
+	if opts != nil {
+		f = *opts
+	}
 
-			switch st := args[i].(type) {
-			case string:
-				key = st
-			default:
-				key = fmt.Sprintf("%s", st)
-			}
-			vals[key] = val
+	resolve := func(name *string, def string) {
+		switch *name {
+		case "":
+			*name = def
+		case "-":
+			*name = ""
 		}
 	}
 
-	encoder := json.NewEncoder(l.writer)
-	encoder.SetEscapeHTML(l.jsonEscapeEnabled)
-	if err := encoder.Encode(vals); err != nil {
-		if _, ok := err.(*json.UnsupportedTypeError); ok {
-			plainVal := l.jsonMapEntry(t, name, level, msg)
-			plainVal["@warn"] = errJsonUnsupportedTypeMsg
+	resolve(&f.Timestamp, "@timestamp")
+	resolve(&f.Level, "@level")
+	resolve(&f.Message, "@message")
+	resolve(&f.Module, "@module")
+	resolve(&f.Caller, "@caller")
+	resolve(&f.Stacktrace, "stacktrace")
 
-			errEncoder := json.NewEncoder(l.writer)
-			errEncoder.SetEscapeHTML(l.jsonEscapeEnabled)
-			_ = errEncoder.Encode(plainVal)
-		}
+	if f.Args == "-" {
+		f.Args = ""
 	}
-}
 
-func (l intLogger) jsonMapEntry(t time.Time, name string, level Level, msg string) map[string]any {
-	vals := map[string]any{
-		"@message": msg,
-	}
-	if !l.disableTime {
-		vals["@timestamp"] = t.Format(l.timeFormat)
-	}
+	return f
+}
 
-	var levelStr string
+// levelName returns the name of a level as written in machine readable
+// formats.
+func levelName(level Level) string {
 	switch level {
 	case Error:
-		levelStr = "error"
+		return "error"
 	case Warn:
-		levelStr = "warn"
+		return "warn"
 	case Info:
-		levelStr = "info"
+		return "info"
 	case Debug:
-		levelStr = "debug"
+		return "debug"
 	case Trace:
-		levelStr = "trace"
+		return "trace"
 	default:
-		levelStr = "all"
+		return "all"
 	}
-
-	vals["@level"] = levelStr
-
-	if name != "" {
-		vals["@module"] = name
-	}
-
-	if l.callerOffset > 0 {
-		if _, file, line, ok := runtime.Caller(l.callerOffset + 1); ok {
-			vals["@caller"] = fmt.Sprintf("%s:%d", file, line)
-		}
-	}
-	return vals
 }
 
 // Emit the message and args at the provided level
@@ -726,6 +869,16 @@ func (l *intLogger) Log(level Level, msg string, args ...any) {
 	l.log(l.Name(), level, msg, args...)
 }
 
+// LogFields emits the message and fields at the provided level. The fields
+// are only boxed once the entry passes the level check.
+func (l *intLogger) LogFields(level Level, msg string, fields ...Field) {
+	if level < l.GetLevel() {
+		return
+	}
+
+	l.log(l.Name(), level, msg, fieldArgs(fields)...)
+}
+
 // Emit the message and args at DEBUG level
 func (l *intLogger) Debug(msg string, args ...any) {
 	l.log(l.Name(), Debug, msg, args...)
@@ -782,44 +935,21 @@ const MissingKey = "EXTRA_VALUE_AT_END"
 // the given key/value pairs. This is used to create a context specific
 // Logger.
 func (l *intLogger) With(args ...any) Logger {
+	args = l.redactor.redact(expandFields(args))
+
 	var extra any
 
-	if len(args)%2 != 0 {
+	if trailingValue(args) {
 		extra = args[len(args)-1]
 		args = args[:len(args)-1]
 	}
 
 	sl := l.copy()
 
-	result := make(map[string]any, len(l.implied)+len(args))
-	keys := make([]string, 0, len(l.implied)+len(args))
-
-	// Read existing args, store map and key for consistent sorting
-	for i := 0; i < len(l.implied); i += 2 {
-		key := l.implied[i].(string)
-		keys = append(keys, key)
-		result[key] = l.implied[i+1]
-	}
-	// Read new args, store map and key for consistent sorting
-	for i := 0; i < len(args); i += 2 {
-		key := args[i].(string)
-		_, exists := result[key]
-		if !exists {
-			keys = append(keys, key)
-		}
-		result[key] = args[i+1]
-	}
-
-	// Sort keys to be consistent
-	sort.Strings(keys)
+	// Keys are sorted to be consistent.
+	sl.implied = mergeArgs(l.implied, wrapGroups(l.groups, args))
 
 	// Don't allocate for MissingKey+extra, they aren't expected to be set often.
-	sl.implied = make([]any, 0, 2*len(result))
-	for _, k := range keys {
-		sl.implied = append(sl.implied, k)
-		sl.implied = append(sl.implied, result[k])
-	}
-
 	if extra != nil {
 		sl.implied = append(sl.implied, MissingKey, extra)
 	}
@@ -827,6 +957,20 @@ func (l *intLogger) With(args ...any) Logger {
 	return l.subloggerHook(sl)
 }
 
+// Return a sub-Logger which nests the key/value pairs given to With and to
+// each entry under the named group, as Group does. Groups given to With
+// with the same name as one of the implied args are merged with it.
+func (l *intLogger) WithGroup(name string) Logger {
+	if name == "" {
+		return l
+	}
+
+	sl := l.copy()
+	sl.groups = append(l.groups[:len(l.groups):len(l.groups)], name)
+
+	return l.subloggerHook(sl)
+}
+
 // Create a new sub-Logger that a name decending from the current name.
 // This is used to create a subsystem specific Logger.
 func (l *intLogger) Named(name string) Logger {
@@ -860,6 +1004,8 @@ func (l *intLogger) ResetOutput(opts *LoggerOptions) error {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
+	l.flushRepeats()
+
 	return l.resetOutput(opts)
 }
 
@@ -874,6 +1020,8 @@ func (l *intLogger) ResetOutputWithFlush(opts *LoggerOptions, flushable Flushabl
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
+	l.flushRepeats()
+
 	if err := flushable.Flush(); err != nil {
 		return err
 	}
@@ -881,6 +1029,26 @@ func (l *intLogger) ResetOutputWithFlush(opts *LoggerOptions, flushable Flushabl
 	return l.resetOutput(opts)
 }
 
+// Flush writes the entry held back by CollapseRepeats, if any, with its
+// repeat count. Call it before the process exits so that the count isn't
+// lost.
+func (l *intLogger) Flush() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.flushRepeats()
+
+	return nil
+}
+
+// flushRepeats writes the entry held back by CollapseRepeats, if any. The
+// mutex must be held by the caller.
+func (l *intLogger) flushRepeats() {
+	if l.repeats != nil {
+		l.repeats.flush()
+	}
+}
+
 func (l *intLogger) resetOutput(opts *LoggerOptions) error {
 	l.writer = newWriter(opts.Output, opts.Color)
 	l.setColorization(opts)
@@ -925,6 +1093,12 @@ func (l *intLogger) searchLevelPtr() *int32 {
 
 // Returns the current level
 func (l *intLogger) GetLevel() Level {
+	if l.levelDirectives != nil {
+		if level := l.levelDirectives.cachedLevel(l.directiveCache, l.directiveRoot, l.name); level != NoLevel {
+			return level
+		}
+	}
+
 	// We perform the loads immediately to keep the CPU pipeline busy, which
 	// effectively makes the second load cost nothing. Once loaded into registers
 	// the comparison returns the already loaded value. The comparison is almost
@@ -996,6 +1170,9 @@ func (i *intLogger) Name() string {
 func (l *intLogger) copy() *intLogger {
 	sl := *l
 
+	// The copy may be renamed, so it can't share the cached directive level.
+	sl.directiveCache = new(atomic.Uint64)
+
 	if l.independentLevels {
 		sl.level = new(int32)
 		*sl.level = *l.level
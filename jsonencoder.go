@@ -0,0 +1,437 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// jsonEncoder writes a JSON object directly to a writer, one field at a time,
+// so that entries can be encoded without building a map first. Strings are
+// escaped exactly as encoding/json escapes them, and values of types other
+// than those with a fast path are encoded with encoding/json.
+type jsonEncoder struct {
+	w          *writer
+	escapeHTML bool
+
+	// structuredErrors writes errors with errorObject rather than as their
+	// message, and errorDepth is how deeply nested the current one is.
+	structuredErrors bool
+	errorDepth       int
+
+	// first is set when the next field is the first of its object.
+	first bool
+}
+
+// begin starts an object.
+func (e *jsonEncoder) begin() {
+	_ = e.w.WriteByte('{')
+	e.first = true
+}
+
+// end finishes an object.
+func (e *jsonEncoder) end() {
+	_ = e.w.WriteByte('}')
+	e.first = false
+}
+
+// key writes the key of the next field.
+func (e *jsonEncoder) key(k string) {
+	if !e.first {
+		_ = e.w.WriteByte(',')
+	}
+	e.first = false
+
+	writeJSONString(e.w, k, e.escapeHTML)
+	_ = e.w.WriteByte(':')
+}
+
+// field writes a field with a string value.
+func (e *jsonEncoder) field(k, v string) {
+	e.key(k)
+	writeJSONString(e.w, v, e.escapeHTML)
+}
+
+// value writes an arg value, converted as by jsonValue.
+func (e *jsonEncoder) value(v any) error {
+	b := e.w.b.AvailableBuffer()
+
+	switch st := v.(type) {
+	case nil:
+		_, _ = e.w.WriteString("null")
+	case Field:
+		return e.fieldValue(st)
+	case jsonObject:
+		e.begin()
+		if err := e.pairs(st); err != nil {
+			return err
+		}
+		e.end()
+	case string:
+		writeJSONString(e.w, st, e.escapeHTML)
+	case Quote:
+		writeJSONString(e.w, string(st), e.escapeHTML)
+	case CapturedStacktrace:
+		writeJSONString(e.w, string(st), e.escapeHTML)
+	case bool:
+		_, _ = e.w.Write(strconv.AppendBool(b, st))
+	case int:
+		_, _ = e.w.Write(strconv.AppendInt(b, int64(st), 10))
+	case int64:
+		_, _ = e.w.Write(strconv.AppendInt(b, st, 10))
+	case int32:
+		_, _ = e.w.Write(strconv.AppendInt(b, int64(st), 10))
+	case int16:
+		_, _ = e.w.Write(strconv.AppendInt(b, int64(st), 10))
+	case int8:
+		_, _ = e.w.Write(strconv.AppendInt(b, int64(st), 10))
+	case uint:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case uint64:
+		_, _ = e.w.Write(strconv.AppendUint(b, st, 10))
+	case uint32:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case uint16:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case uint8:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case Hex:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case Octal:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case Binary:
+		_, _ = e.w.Write(strconv.AppendUint(b, uint64(st), 10))
+	case time.Duration:
+		_, _ = e.w.Write(strconv.AppendInt(b, int64(st), 10))
+	case float64:
+		return e.float(st, 64)
+	case float32:
+		return e.float(float64(st), 32)
+	case Format:
+		writeJSONString(e.w, fmt.Sprintf(st[0].(string), st[1:]...), e.escapeHTML)
+	case json.Marshaler, encoding.TextMarshaler:
+		return e.marshal(st)
+	case error:
+		if e.structuredErrors {
+			return e.errorObject(st)
+		}
+		writeJSONString(e.w, st.Error(), e.escapeHTML)
+	default:
+		return e.marshal(st)
+	}
+
+	return nil
+}
+
+// float writes f as encoding/json does.
+func (e *jsonEncoder) float(f float64, bits int) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	b := strconv.AppendFloat(e.w.b.AvailableBuffer(), f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+
+	_, _ = e.w.Write(b)
+
+	return nil
+}
+
+// marshal writes v using encoding/json.
+func (e *jsonEncoder) marshal(v any) error {
+	encoder := json.NewEncoder(e.w)
+	encoder.SetEscapeHTML(e.escapeHTML)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	// Remove the newline added by Encode
+	e.w.b.Truncate(e.w.b.Len() - 1)
+
+	return nil
+}
+
+// writeJSONString writes s as a quoted JSON string.
+func writeJSONString(w *writer, s string, escapeHTML bool) {
+	_ = w.WriteByte('"')
+	writeJSONChars(w, s, escapeHTML)
+	_ = w.WriteByte('"')
+}
+
+// writeJSONChars writes s escaped for use in a JSON string, without the
+// quotes. Like encoding/json, invalid UTF-8 is replaced with U+FFFD and
+// U+2028 and U+2029 are escaped so the output is safe to embed in JavaScript.
+func writeJSONChars(w *writer, s string, escapeHTML bool) {
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= ' ' && c != '"' && c != '\\' && (!escapeHTML || (c != '<' && c != '>' && c != '&')) {
+				i++
+				continue
+			}
+
+			_, _ = w.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				_ = w.WriteByte('\\')
+				_ = w.WriteByte(c)
+			case '\b':
+				_, _ = w.WriteString(`\b`)
+			case '\f':
+				_, _ = w.WriteString(`\f`)
+			case '\n':
+				_, _ = w.WriteString(`\n`)
+			case '\r':
+				_, _ = w.WriteString(`\r`)
+			case '\t':
+				_, _ = w.WriteString(`\t`)
+			default:
+				_, _ = w.WriteString(`\u00`)
+				_ = w.WriteByte(lowerhex[c>>4])
+				_ = w.WriteByte(lowerhex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			_, _ = w.WriteString(s[start:i])
+			_, _ = w.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+
+		if r == '\u2028' || r == '\u2029' {
+			_, _ = w.WriteString(s[start:i])
+			_, _ = w.WriteString(`\u202`)
+			_ = w.WriteByte(lowerhex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+
+		i += size
+	}
+
+	_, _ = w.WriteString(s[start:])
+}
+
+// logJSON writes the entry as a JSON object. The envelope fields are written
+// first, in a fixed order, followed by the implied args and then the args in
+// the order given. Each key is written once: an arg with the same key as an
+// envelope field or an earlier arg replaces its value, so the last value
+// given for a key is written where the key first appears.
+//
+// If an arg value can't be encoded, the entry is written without its args and
+// with a warning instead.
+func (l *intLogger) logJSON(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	start := l.writer.b.Len()
+
+	e := jsonEncoder{
+		w:                l.writer,
+		escapeHTML:       l.jsonEscapeEnabled,
+		structuredErrors: l.structuredErrors,
+	}
+
+	args, stacktrace := splitStacktrace(args)
+
+	var buf [16]jsonPair
+	pairs := l.jsonEnvelope(buf[:0], t, pc, name, level, msg, stacktrace)
+
+	if len(l.groups) > 0 {
+		// The groups of args are also in implied, and must be written once.
+		implied, args = joinGroups(implied, args), nil
+	}
+
+	if l.jsonFields.Args == "" {
+		pairs = appendJSONPairs(pairs, implied, args)
+	} else if len(implied) > 0 || len(args) > 0 {
+		nested := appendJSONPairs(nil, implied, args)
+
+		// The nested args are written as an object with sorted keys, as a
+		// map value is.
+		slices.SortStableFunc(nested, func(a, b jsonPair) int {
+			return strings.Compare(a.key, b.key)
+		})
+
+		pairs = append(pairs, jsonPair{key: l.jsonFields.Args, val: jsonObject(nested)})
+	}
+
+	e.begin()
+	if err := e.pairs(pairs); err != nil {
+		l.writer.b.Truncate(start)
+
+		e.begin()
+		_ = e.pairs(l.jsonEnvelope(pairs[:0], t, pc, name, level, msg, stacktrace))
+		e.field("@warn", errJsonUnsupportedTypeMsg)
+	}
+
+	e.end()
+	_ = l.writer.WriteByte('\n')
+}
+
+// jsonPair is a field of a JSON object which is yet to be written. The
+// envelope fields hold their value in str, and line for the caller, so that
+// it isn't boxed.
+type jsonPair struct {
+	key  string
+	kind jsonPairKind
+	str  string
+	line int
+	val  any
+}
+
+type jsonPairKind uint8
+
+const (
+	jsonPairValue jsonPairKind = iota
+	jsonPairString
+	jsonPairCaller
+)
+
+// jsonObject is a value written as an object with the given fields.
+type jsonObject []jsonPair
+
+// jsonEnvelope appends the fields describing the entry itself to pairs.
+func (l *intLogger) jsonEnvelope(pairs []jsonPair, t time.Time, pc uintptr, name string, level Level, msg string, stacktrace CapturedStacktrace) []jsonPair {
+	f := &l.jsonFields
+
+	if f.Timestamp != "" && !l.disableTime && !t.IsZero() {
+		pairs = append(pairs, jsonPair{key: f.Timestamp, kind: jsonPairString, str: t.Format(l.timeFormat)})
+	}
+
+	if f.Level != "" {
+		pairs = append(pairs, jsonPair{key: f.Level, kind: jsonPairString, str: levelName(level)})
+	}
+
+	if f.Message != "" {
+		pairs = append(pairs, jsonPair{key: f.Message, kind: jsonPairString, str: msg})
+	}
+
+	if f.Module != "" && name != "" {
+		pairs = append(pairs, jsonPair{key: f.Module, kind: jsonPairString, str: name})
+	}
+
+	if f.Caller != "" && l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			pairs = append(pairs, jsonPair{key: f.Caller, kind: jsonPairCaller, str: file, line: line})
+		}
+	}
+
+	if stacktrace != "" && f.Stacktrace != "" {
+		pairs = append(pairs, jsonPair{key: f.Stacktrace, kind: jsonPairString, str: string(stacktrace)})
+	}
+
+	return pairs
+}
+
+// jsonPairsIndexed is the number of pairs past which appendJSONPairs finds
+// the pair with a key through a map, rather than by comparing it with each.
+const jsonPairsIndexed = 16
+
+// appendJSONPairs appends the fields of each of args to pairs, skipping empty
+// groups. A field with the key of one already in pairs replaces its value
+// rather than being appended.
+func appendJSONPairs(pairs []jsonPair, args ...[]any) []jsonPair {
+	var index map[string]int
+
+	for _, args := range args {
+		for i := 0; i < len(args); {
+			var (
+				key string
+				val any
+			)
+
+			key, val, i = nextArg(args, i)
+
+			if f, ok := val.(Field); ok {
+				if group, ok := f.group(); ok && len(group) == 0 {
+					continue
+				}
+			}
+
+			j := -1
+			if index != nil {
+				if k, ok := index[key]; ok {
+					j = k
+				}
+			} else {
+				j = slices.IndexFunc(pairs, func(p jsonPair) bool { return p.key == key })
+			}
+
+			if j >= 0 {
+				pairs[j] = jsonPair{key: key, val: val}
+				continue
+			}
+
+			pairs = append(pairs, jsonPair{key: key, val: val})
+
+			switch {
+			case index != nil:
+				index[key] = len(pairs) - 1
+			case len(pairs) > jsonPairsIndexed:
+				index = make(map[string]int, 2*len(pairs))
+				for k := len(pairs) - 1; k >= 0; k-- {
+					index[pairs[k].key] = k
+				}
+			}
+		}
+	}
+
+	return pairs
+}
+
+// pairs writes the given fields.
+func (e *jsonEncoder) pairs(pairs []jsonPair) error {
+	for _, p := range pairs {
+		e.key(p.key)
+
+		switch p.kind {
+		case jsonPairString:
+			writeJSONString(e.w, p.str, e.escapeHTML)
+		case jsonPairCaller:
+			_ = e.w.WriteByte('"')
+			writeJSONChars(e.w, p.str, e.escapeHTML)
+			_ = e.w.WriteByte(':')
+			_, _ = e.w.Write(strconv.AppendInt(e.w.b.AvailableBuffer(), int64(p.line), 10))
+			_ = e.w.WriteByte('"')
+		default:
+			if err := e.value(p.val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// args writes the fields of args as pairs does, with one field for each key.
+func (e *jsonEncoder) args(args []any) error {
+	var buf [16]jsonPair
+	return e.pairs(appendJSONPairs(buf[:0], args))
+}
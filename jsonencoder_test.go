@@ -0,0 +1,411 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logJSONMap is the map based implementation logJSON replaced. It's kept to
+// check the output of the two against each other and to benchmark against.
+func (l *intLogger) logJSONMap(t time.Time, pc uintptr, name string, level Level, msg string, args ...any) {
+	vals := l.jsonMapEntry(t, pc, name, level, msg)
+	args = append(l.implied, args...)
+
+	if len(args) > 0 {
+		if len(args)%2 != 0 {
+			cs, ok := args[len(args)-1].(CapturedStacktrace)
+			if ok {
+				args = args[:len(args)-1]
+				if l.jsonFields.Stacktrace != "" {
+					vals[l.jsonFields.Stacktrace] = cs
+				}
+			} else {
+				extra := args[len(args)-1]
+				args = append(args[:len(args)-1], MissingKey, extra)
+			}
+		}
+
+		fields := vals
+		if l.jsonFields.Args != "" && len(args) > 0 {
+			fields = make(map[string]any, len(args)/2)
+			vals[l.jsonFields.Args] = fields
+		}
+
+		for i := 0; i < len(args); i = i + 2 {
+			fields[argKey(args[i])] = jsonValue(args[i+1])
+		}
+	}
+
+	encoder := json.NewEncoder(l.writer)
+	encoder.SetEscapeHTML(l.jsonEscapeEnabled)
+	if err := encoder.Encode(vals); err != nil {
+		if _, ok := err.(*json.UnsupportedTypeError); ok {
+			plainVal := l.jsonMapEntry(t, pc, name, level, msg)
+			plainVal["@warn"] = errJsonUnsupportedTypeMsg
+
+			errEncoder := json.NewEncoder(l.writer)
+			errEncoder.SetEscapeHTML(l.jsonEscapeEnabled)
+			_ = errEncoder.Encode(plainVal)
+		}
+	}
+}
+
+func (l *intLogger) jsonMapEntry(t time.Time, pc uintptr, name string, level Level, msg string) map[string]any {
+	f := &l.jsonFields
+
+	vals := map[string]any{}
+	if f.Message != "" {
+		vals[f.Message] = msg
+	}
+	if f.Timestamp != "" && !l.disableTime && !t.IsZero() {
+		vals[f.Timestamp] = t.Format(l.timeFormat)
+	}
+
+	if f.Level != "" {
+		vals[f.Level] = levelName(level)
+	}
+
+	if f.Module != "" && name != "" {
+		vals[f.Module] = name
+	}
+
+	if f.Caller != "" && l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			vals[f.Caller] = fmt.Sprintf("%s:%d", file, line)
+		}
+	}
+	return vals
+}
+
+// newJSONTestLogger returns a logger writing JSON that isn't flushed, so the
+// entries it encodes can be read from its writer.
+func newJSONTestLogger(opts *LoggerOptions) *intLogger {
+	opts.Output = &bytes.Buffer{}
+	opts.JSONFormat = true
+	opts.TimeFn = func() time.Time {
+		return time.Date(2026, 3, 4, 5, 6, 7, 891234000, time.UTC)
+	}
+
+	return New(opts).(*intLogger)
+}
+
+type jsonTextValue struct{ s string }
+
+func (v jsonTextValue) MarshalText() ([]byte, error) {
+	return []byte("text:" + v.s), nil
+}
+
+type jsonMarshalerError struct{}
+
+func (jsonMarshalerError) Error() string { return "plain" }
+
+func (jsonMarshalerError) MarshalJSON() ([]byte, error) {
+	return []byte(`{"code":42}`), nil
+}
+
+func TestJSONEncoder(t *testing.T) {
+	t.Run("matches the map based encoder", func(t *testing.T) {
+		cases := []struct {
+			name string
+			opts LoggerOptions
+			with []any
+			args []any
+		}{
+			{
+				name: "no args",
+			},
+			{
+				name: "primitives",
+				args: []any{
+					"s", "str", "b", true, "i", -1, "i8", int8(-8), "i16", int16(-16),
+					"i32", int32(-32), "i64", int64(math.MinInt64), "u", uint(1),
+					"u8", uint8(8), "u16", uint16(16), "u32", uint32(32),
+					"u64", uint64(math.MaxUint64), "nil", nil,
+				},
+			},
+			{
+				name: "floats",
+				args: []any{
+					"f64", 1.5, "zero", 0.0, "neg", -0.0, "small", 1e-7, "large", 1e21,
+					"f32", float32(3.14), "f32small", float32(1e-7), "f32large", float32(1e21),
+					"int", 100.0, "max", math.MaxFloat64,
+				},
+			},
+			{
+				name: "hclog types",
+				args: []any{
+					"hex", Hex(255), "octal", Octal(8), "binary", Binary(5),
+					"quote", Quote("q\"q"), "format", Fmt("%d-%s", 1, "a"),
+					"dur", 1500 * time.Millisecond,
+				},
+			},
+			{
+				name: "errors and marshalers",
+				args: []any{
+					"err", errors.New("boom"),
+					"jerr", jsonMarshalerError{},
+					"text", jsonTextValue{"v"},
+					"time", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+			{
+				name: "composites",
+				args: []any{
+					"slice", []string{"a", "b"}, "map", map[string]int{"b": 2, "a": 1},
+					"struct", struct{ A int }{1}, "ptr", &struct{ B string }{"b"},
+				},
+			},
+			{
+				name: "escaping",
+				args: []any{
+					"ctl", "a\x00b\x1fc\b\f\n\r\t", "html", "<a href='x'>&</a>",
+					"invalid", "a\xffb", "seps", "a b c", "uni", "héllo 世界",
+					"key\"with\nescapes", "v",
+				},
+			},
+			{
+				name: "html escaping disabled",
+				opts: LoggerOptions{JSONEscapeDisabled: true},
+				args: []any{"html", "<a>&</a>"},
+			},
+			{
+				name: "implied args and stacktrace",
+				opts: LoggerOptions{Name: "app"},
+				with: []any{"peer", "10.0.0.1"},
+				args: []any{"status", 200, CapturedStacktrace("stack")},
+			},
+			{
+				name: "missing key",
+				args: []any{"a", 1, "extra"},
+			},
+			{
+				name: "colliding keys",
+				args: []any{"@message", "overwritten", "a", 1, "a", 2},
+			},
+			{
+				name: "non-string keys",
+				args: []any{1, "one", Hex(2), "two"},
+			},
+			{
+				name: "location",
+				opts: LoggerOptions{IncludeLocation: true},
+				args: []any{"a", 1},
+			},
+			{
+				name: "renamed fields",
+				opts: LoggerOptions{
+					Name: "app",
+					JSONFields: &JSONFields{
+						Timestamp: "ts",
+						Level:     "-",
+						Message:   "msg",
+						Args:      "fields",
+					},
+				},
+				with: []any{"peer", "10.0.0.1"},
+				args: []any{"a", 1, "extra"},
+			},
+			{
+				name: "unsupported type",
+				args: []any{"a", 1, "ch", make(chan int)},
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				l := newJSONTestLogger(&c.opts)
+				if len(c.with) > 0 {
+					l = l.With(c.with...).(*intLogger)
+				}
+
+				now := l.timeFn()
+
+				l.logJSON(now, 0, l.name, Info, "msg <&>", l.implied, c.args)
+				stream := l.writer.b.String()
+				l.writer.b.Reset()
+
+				l.logJSONMap(now, 0, l.name, Info, "msg <&>", c.args...)
+				mapped := l.writer.b.String()
+				l.writer.b.Reset()
+
+				require.True(t, json.Valid([]byte(stream)), stream)
+				assert.JSONEq(t, mapped, stream)
+			})
+		}
+	})
+
+	t.Run("writes fields in a fixed order", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:       "app",
+			Output:     &buf,
+			JSONFormat: true,
+			TimeFn: func() time.Time {
+				return time.Date(2026, 3, 4, 5, 6, 7, 891234000, time.UTC)
+			},
+		})
+
+		// With sorts the implied args by key.
+		logger.With("zeta", 1, "alpha", 2).Warn("ordered", "mu", 3, "beta", "b", CapturedStacktrace("stack"))
+
+		assert.Equal(t,
+			`{"@timestamp":"2026-03-04T05:06:07.891234Z","@level":"warn","@message":"ordered","@module":"app","stacktrace":"stack","alpha":2,"zeta":1,"mu":3,"beta":"b"}`+"\n",
+			buf.String())
+	})
+
+	t.Run("writes each key once", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:        "app",
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		logger.With("id", 1).Info("x", "id", 2, "a", "b", "id", 3)
+		logger.Info("y", "@level", "fake", Group("req", "n", 1, "n", 2), Group("empty"), "empty", 3)
+
+		assert.Equal(t,
+			`{"@level":"info","@message":"x","@module":"app","id":3,"a":"b"}`+"\n"+
+				`{"@level":"fake","@message":"y","@module":"app","req":{"n":2},"empty":3}`+"\n",
+			buf.String())
+	})
+
+	t.Run("writes each key of many once", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		var args []any
+		want := `{"@level":"info","@message":"many"`
+		for i := range 2 * jsonPairsIndexed {
+			args = append(args, fmt.Sprintf("k%d", i), i)
+			if i == 3 {
+				want += fmt.Sprintf(`,"k%d":"last"`, i)
+			} else {
+				want += fmt.Sprintf(`,"k%d":%d`, i, i)
+			}
+		}
+		args = append(args, "k3", "last", "@message", "many")
+
+		logger.Info("first", args...)
+
+		assert.Equal(t, want+"}\n", buf.String())
+	})
+
+	t.Run("is stable across entries", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		args := []any{"c", 3, "b", map[string]int{"z": 1, "y": 2}, "a", 1}
+
+		logger.Info("entry", args...)
+		first := buf.String()
+
+		for range 50 {
+			buf.Reset()
+			logger.Info("entry", args...)
+			require.Equal(t, first, buf.String())
+		}
+
+		assert.Equal(t, `{"@level":"info","@message":"entry","c":3,"b":{"y":2,"z":1},"a":1}`+"\n", first)
+	})
+
+	t.Run("writes floats as encoding/json does", func(t *testing.T) {
+		for _, f := range []float64{0, 1, -1, 0.1, 1.5, 1e-6, 1e-7, 123456789, 1e20, 1e21, 1.7976931348623157e308, 5e-324} {
+			want, err := json.Marshal(f)
+			require.NoError(t, err)
+
+			var w writer
+			e := jsonEncoder{w: &w}
+			require.NoError(t, e.value(f))
+			assert.Equal(t, string(want), w.b.String())
+
+			if math.IsInf(float64(float32(f)), 0) {
+				continue
+			}
+
+			want, err = json.Marshal(float32(f))
+			require.NoError(t, err)
+
+			w.b.Reset()
+			require.NoError(t, e.value(float32(f)))
+			assert.Equal(t, string(want), w.b.String())
+		}
+	})
+
+	t.Run("warns about values that can't be encoded", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+		})
+
+		logger.Info("bad", "a", 1, "nan", math.NaN())
+		logger.Info("worse", "fn", func() {})
+
+		assert.Equal(t,
+			`{"@level":"info","@message":"bad","@warn":"`+errJsonUnsupportedTypeMsg+`"}`+"\n"+
+				`{"@level":"info","@message":"worse","@warn":"`+errJsonUnsupportedTypeMsg+`"}`+"\n",
+			buf.String())
+	})
+}
+
+func BenchmarkLogJSON(b *testing.B) {
+	args := []any{
+		"name", "foo",
+		"what", "benchmarking yourself",
+		"count", 42,
+		"ratio", 0.75,
+		"ok", true,
+		"elapsed", 1500 * time.Millisecond,
+		"err", errors.New("boom"),
+		"k8", "value",
+		"k9", "value",
+		"k10", "value",
+	}
+
+	l := newJSONTestLogger(&LoggerOptions{Name: "test"})
+	l = l.With("request_id", "abc123").(*intLogger)
+	now := l.timeFn()
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.logJSON(now, 0, l.name, Info, "this is some message", l.implied, args)
+			l.writer.b.Reset()
+		}
+	})
+
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.logJSONMap(now, 0, l.name, Info, "this is some message", args...)
+			l.writer.b.Reset()
+		}
+	})
+}
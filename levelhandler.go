@@ -0,0 +1,245 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LevelHandler is an http.Handler which reports and changes the levels of a
+// tree of loggers at runtime. Loggers are tracked by name: the root logger is
+// added with Register, and subloggers are added as they are created by setting
+// SubloggerHook as the LoggerOptions.SubloggerHook of the root. Only the first
+// logger created with a given name is tracked, so loggers created via With do
+// not accumulate. Tracked loggers are held until the handler is discarded, and
+// at most MaxLevelHandlerLoggers names are tracked, so that subloggers named
+// after something unbounded, such as a request ID, can't grow it without
+// limit.
+//
+// A GET request returns the tracked loggers and their current levels as JSON:
+//
+//	[{"name":"app","level":"info"},{"name":"app.raft","level":"debug"}]
+//
+// A PUT or POST request changes the level of a logger using SetLevel. The body
+// is a JSON object giving the level, and optionally the name of the logger and
+// a duration after which the previous level is restored:
+//
+//	{"name":"app.raft","level":"trace","ttl":"10m"}
+//
+// When the name is omitted the root logger is changed. The response has the
+// same form as for a GET request.
+//
+// Changing the level of a sublogger without also changing its parent and
+// siblings requires the loggers to be created with SyncParentLevel or
+// IndependentLevels, see LoggerOptions. Loggers whose level is assigned by
+// LevelDirectives ignore SetLevel, so a request to change one fails with 409
+// Conflict.
+type LevelHandler struct {
+	mu      sync.Mutex
+	root    string
+	loggers map[string]Logger
+	reverts map[string]*levelRevert
+}
+
+// MaxLevelHandlerLoggers is the most loggers a LevelHandler tracks. Loggers
+// registered after that are ignored.
+const MaxLevelHandlerLoggers = 1024
+
+// levelRevert is a pending restoration of a logger's level.
+type levelRevert struct {
+	level Level
+	at    time.Time
+	timer *time.Timer
+}
+
+// levelHandlerEntry is the JSON form of a tracked logger.
+type levelHandlerEntry struct {
+	Name     string     `json:"name"`
+	Level    string     `json:"level"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// levelHandlerRequest is the JSON body of a PUT or POST request.
+type levelHandlerRequest struct {
+	Name  *string `json:"name"`
+	Level string  `json:"level"`
+	TTL   string  `json:"ttl"`
+}
+
+// NewLevelHandler returns a LevelHandler which is not yet tracking any
+// loggers. For example:
+//
+//	levels := hclog.NewLevelHandler()
+//	logger := hclog.New(&hclog.LoggerOptions{
+//		Name:            "app",
+//		SyncParentLevel: true,
+//		SubloggerHook:   levels.SubloggerHook,
+//	})
+//	levels.Register(logger)
+//	http.Handle("/debug/log-levels", levels)
+func NewLevelHandler() *LevelHandler {
+	return &LevelHandler{
+		loggers: make(map[string]Logger),
+		reverts: make(map[string]*levelRevert),
+	}
+}
+
+// Register adds the logger to the handler, unless a logger with the same name
+// is already tracked or MaxLevelHandlerLoggers are. The first logger
+// registered is the root logger.
+func (h *LevelHandler) Register(logger Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := logger.Name()
+	if _, ok := h.loggers[name]; ok || len(h.loggers) >= MaxLevelHandlerLoggers {
+		return
+	}
+
+	if len(h.loggers) == 0 {
+		h.root = name
+	}
+
+	h.loggers[name] = logger
+}
+
+// SubloggerHook registers the sublogger with the handler and returns it
+// unchanged. It is intended to be used as LoggerOptions.SubloggerHook.
+func (h *LevelHandler) SubloggerHook(sub Logger) Logger {
+	h.Register(sub)
+	return sub
+}
+
+// ServeHTTP implements http.Handler.
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		if status, err := h.update(r); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.entries())
+}
+
+// update applies a level change request, returning the status code to respond
+// with if it fails.
+func (h *LevelHandler) update(r *http.Request) (int, error) {
+	var req levelHandlerRequest
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	level := LevelFromString(req.Level)
+	if level == NoLevel {
+		return http.StatusBadRequest, fmt.Errorf("invalid level %q", req.Level)
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return http.StatusBadRequest, fmt.Errorf("invalid ttl %q", req.TTL)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := h.root
+	if req.Name != nil {
+		name = *req.Name
+	}
+
+	logger, ok := h.loggers[name]
+	if !ok {
+		return http.StatusNotFound, fmt.Errorf("unknown logger %q", name)
+	}
+
+	current := logger.GetLevel()
+
+	// A logger whose level is assigned by LevelDirectives ignores SetLevel,
+	// which is reported rather than responding as if the level had changed.
+	logger.SetLevel(level)
+	if logger.GetLevel() != level {
+		logger.SetLevel(current)
+		return http.StatusConflict, fmt.Errorf("the level of logger %q is assigned by LevelDirectives, and can't be changed to %s", name, level)
+	}
+
+	// A pending revert restores the level from before the first change, so
+	// repeated temporary changes don't make a temporary level permanent.
+	previous := current
+	if rv, ok := h.reverts[name]; ok {
+		rv.timer.Stop()
+		delete(h.reverts, name)
+		previous = rv.level
+	}
+
+	if ttl > 0 {
+		rv := &levelRevert{
+			level: previous,
+			at:    time.Now().Add(ttl),
+		}
+		rv.timer = time.AfterFunc(ttl, func() {
+			h.revert(name, rv)
+		})
+		h.reverts[name] = rv
+	}
+
+	return http.StatusOK, nil
+}
+
+// revert restores the level saved in rv, unless it has since been replaced by
+// another change.
+func (h *LevelHandler) revert(name string, rv *levelRevert) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.reverts[name] != rv {
+		return
+	}
+
+	delete(h.reverts, name)
+	h.loggers[name].SetLevel(rv.level)
+}
+
+func (h *LevelHandler) entries() []levelHandlerEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := make([]levelHandlerEntry, 0, len(h.loggers))
+	for name, logger := range h.loggers {
+		entry := levelHandlerEntry{
+			Name:  name,
+			Level: logger.GetLevel().String(),
+		}
+		if rv, ok := h.reverts[name]; ok {
+			at := rv.at
+			entry.RevertAt = &at
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
@@ -0,0 +1,195 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelHandler(t *testing.T) {
+	setup := func() (*LevelHandler, Logger) {
+		levels := NewLevelHandler()
+		logger := New(&LoggerOptions{
+			Name:            "app",
+			Level:           Info,
+			SyncParentLevel: true,
+			SubloggerHook:   levels.SubloggerHook,
+		})
+		levels.Register(logger)
+
+		return levels, logger
+	}
+
+	do := func(t *testing.T, h http.Handler, method, body string) (*httptest.ResponseRecorder, []levelHandlerEntry) {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(body)))
+
+		var entries []levelHandlerEntry
+		if rec.Code == http.StatusOK {
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		}
+
+		return rec, entries
+	}
+
+	t.Run("lists the logger tree", func(t *testing.T) {
+		levels, logger := setup()
+
+		raft := logger.Named("raft")
+		raft.Named("fsm")
+		raft.With("peer", "a")
+		logger.Named("http")
+
+		_, entries := do(t, levels, http.MethodGet, "")
+		assert.Equal(t, []levelHandlerEntry{
+			{Name: "app", Level: "info"},
+			{Name: "app.http", Level: "info"},
+			{Name: "app.raft", Level: "info"},
+			{Name: "app.raft.fsm", Level: "info"},
+		}, entries)
+	})
+
+	t.Run("changes the level of a sublogger", func(t *testing.T) {
+		levels, logger := setup()
+
+		raft := logger.Named("raft")
+		fsm := raft.Named("fsm")
+		srv := logger.Named("http")
+
+		rec, entries := do(t, levels, "PUT", `{"name":"app.raft","level":"debug"}`)
+		require.Equal(t, 200, rec.Code)
+		assert.Equal(t, []levelHandlerEntry{
+			{Name: "app", Level: "info"},
+			{Name: "app.http", Level: "info"},
+			{Name: "app.raft", Level: "debug"},
+			{Name: "app.raft.fsm", Level: "debug"},
+		}, entries)
+
+		assert.Equal(t, Debug, raft.GetLevel())
+		assert.Equal(t, Debug, fsm.GetLevel())
+		assert.Equal(t, Info, srv.GetLevel())
+		assert.Equal(t, Info, logger.GetLevel())
+	})
+
+	t.Run("changes the level of the root", func(t *testing.T) {
+		levels, logger := setup()
+
+		raft := logger.Named("raft")
+
+		rec, _ := do(t, levels, "POST", `{"level":"warn"}`)
+		require.Equal(t, 200, rec.Code)
+
+		assert.Equal(t, Warn, logger.GetLevel())
+		assert.Equal(t, Warn, raft.GetLevel())
+	})
+
+	t.Run("reverts after the ttl", func(t *testing.T) {
+		levels, logger := setup()
+
+		logger.Named("raft")
+
+		rec, entries := do(t, levels, "PUT", `{"name":"app.raft","level":"trace","ttl":"50ms"}`)
+		require.Equal(t, 200, rec.Code)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "trace", entries[1].Level)
+		assert.NotNil(t, entries[1].RevertAt)
+
+		// A second temporary change still reverts to the original level.
+		rec, _ = do(t, levels, "PUT", `{"name":"app.raft","level":"debug","ttl":"50ms"}`)
+		require.Equal(t, 200, rec.Code)
+
+		assert.Eventually(t, func() bool {
+			_, entries := do(t, levels, http.MethodGet, "")
+			return entries[1].Level == "info" && entries[1].RevertAt == nil
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("a permanent change cancels a pending revert", func(t *testing.T) {
+		levels, logger := setup()
+
+		logger.Named("raft")
+
+		rec, _ := do(t, levels, "PUT", `{"name":"app.raft","level":"trace","ttl":"20ms"}`)
+		require.Equal(t, 200, rec.Code)
+
+		rec, _ = do(t, levels, "PUT", `{"name":"app.raft","level":"error"}`)
+		require.Equal(t, 200, rec.Code)
+
+		time.Sleep(50 * time.Millisecond)
+
+		_, entries := do(t, levels, http.MethodGet, "")
+		assert.Equal(t, "error", entries[1].Level)
+	})
+
+	t.Run("rejects changes to loggers with directives", func(t *testing.T) {
+		d, err := ParseLevelDirectives("raft=debug")
+		require.NoError(t, err)
+
+		levels := NewLevelHandler()
+		logger := New(&LoggerOptions{
+			Name:              "app",
+			Level:             Info,
+			IndependentLevels: true,
+			LevelDirectives:   d,
+			SubloggerHook:     levels.SubloggerHook,
+		})
+		levels.Register(logger)
+		raft := logger.Named("raft")
+
+		rec, _ := do(t, levels, "PUT", `{"name":"app.raft","level":"trace","ttl":"1h"}`)
+		assert.Equal(t, 409, rec.Code)
+		assert.Equal(t, "the level of logger \"app.raft\" is assigned by LevelDirectives, and can't be changed to trace\n", rec.Body.String())
+		assert.Equal(t, Debug, raft.GetLevel())
+
+		_, entries := do(t, levels, "GET", "")
+		assert.Equal(t, []levelHandlerEntry{
+			{Name: "app", Level: "info"},
+			{Name: "app.raft", Level: "debug"},
+		}, entries)
+	})
+
+	t.Run("tracks a limited number of loggers", func(t *testing.T) {
+		levels, logger := setup()
+
+		for i := range MaxLevelHandlerLoggers + 10 {
+			logger.Named(strconv.Itoa(i))
+		}
+
+		_, entries := do(t, levels, "GET", "")
+		assert.Len(t, entries, MaxLevelHandlerLoggers)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		levels, _ := setup()
+
+		rec, _ := do(t, levels, "PUT", `{"level":"loud"}`)
+		assert.Equal(t, 400, rec.Code)
+		assert.Equal(t, "invalid level \"loud\"\n", rec.Body.String())
+
+		rec, _ = do(t, levels, "PUT", `{"level":"debug","ttl":"soon"}`)
+		assert.Equal(t, 400, rec.Code)
+
+		rec, _ = do(t, levels, "PUT", `{"level":"debug","color":true}`)
+		assert.Equal(t, 400, rec.Code)
+
+		rec, _ = do(t, levels, "PUT", `{"name":"app.nope","level":"debug"}`)
+		assert.Equal(t, 404, rec.Code)
+
+		rec, _ = do(t, levels, "DELETE", "")
+		assert.Equal(t, 405, rec.Code)
+		assert.Equal(t, "GET, HEAD, PUT, POST", rec.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// logLogfmt writes the entry as a single line of logfmt. Unlike the plain
+// format, keys are always valid logfmt keys, with any space, '=', '"' or
+// control character replaced by '_', and values are quoted whenever they are
+// empty or contain anything other than printable, non-space characters, so
+// that multi-line values and stacktraces stay on the one line. Args whose
+// keys are among those written for every entry, such as msg or level, have
+// them prefixed with underscores so that they can't be mistaken for them or
+// for the keys of other args.
+func (l *intLogger) logLogfmt(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	pairs, stacktrace := splitArgs(implied, args)
+
+	if !l.disableTime && !t.IsZero() {
+		_, _ = l.writer.WriteString("ts=")
+		writeLogfmtValue(l.writer, t.Format(l.timeFormat))
+		_ = l.writer.WriteByte(' ')
+	}
+
+	_, _ = l.writer.WriteString("level=")
+	_, _ = l.writer.WriteString(levelName(level))
+
+	if name != "" {
+		_, _ = l.writer.WriteString(" module=")
+		writeLogfmtValue(l.writer, name)
+	}
+
+	_, _ = l.writer.WriteString(" msg=")
+	writeLogfmtValue(l.writer, msg)
+
+	if l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			_, _ = l.writer.WriteString(" caller=")
+			writeLogfmtValue(l.writer, file+":"+strconv.Itoa(line))
+		}
+	}
+
+	keys := logfmtKeys(pairs)
+
+	for i := 0; i < len(pairs); i += 2 {
+		var val string
+		switch st := pairs[i+1].(type) {
+		case string:
+			val = st
+		case Quote:
+			val = string(st)
+		default:
+			val, _ = l.plainValue(st)
+		}
+
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(keys[i/2])
+		_ = l.writer.WriteByte('=')
+		writeLogfmtValue(l.writer, val)
+	}
+
+	if stacktrace != "" {
+		_, _ = l.writer.WriteString(" stacktrace=")
+		writeLogfmtValue(l.writer, string(stacktrace))
+	}
+
+	_ = l.writer.WriteByte('\n')
+}
+
+// logfmtReservedKeys are the keys logLogfmt writes itself.
+var logfmtReservedKeys = map[string]bool{
+	"ts":         true,
+	"level":      true,
+	"module":     true,
+	"msg":        true,
+	"caller":     true,
+	"stacktrace": true,
+}
+
+// isLogfmtKeyByte reports whether c may appear in a logfmt key or unquoted
+// value.
+func isLogfmtKeyByte(c byte) bool {
+	return c > ' ' && c != '=' && c != '"' && c != 0x7f
+}
+
+// logfmtKeys returns the keys to write for pairs. A key which is among those
+// written for every entry is prefixed with underscores until it is neither
+// one of those nor the key of another pair, so that it can't be mistaken for
+// either.
+func logfmtKeys(pairs []any) []string {
+	keys := make([]string, len(pairs)/2)
+
+	reserved := false
+	for i := range keys {
+		keys[i] = logfmtKey(argKey(pairs[2*i]))
+		reserved = reserved || logfmtReservedKeys[keys[i]]
+	}
+
+	if !reserved {
+		return keys
+	}
+
+	taken := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		taken[key] = true
+	}
+
+	for i, key := range keys {
+		if !logfmtReservedKeys[key] {
+			continue
+		}
+
+		for logfmtReservedKeys[key] || taken[key] {
+			key = "_" + key
+		}
+
+		taken[key] = true
+		keys[i] = key
+	}
+
+	return keys
+}
+
+// logfmtKey returns key with any bytes not permitted in a key replaced.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	for i := 0; i < len(key); i++ {
+		if !isLogfmtKeyByte(key[i]) {
+			b := []byte(key)
+			for j := i; j < len(b); j++ {
+				if !isLogfmtKeyByte(b[j]) {
+					b[j] = '_'
+				}
+			}
+
+			return string(b)
+		}
+	}
+
+	return key
+}
+
+// logfmtNeedsQuoting reports whether val must be quoted to be read back as a
+// single value.
+func logfmtNeedsQuoting(val string) bool {
+	if val == "" {
+		return true
+	}
+
+	for i := 0; i < len(val); i++ {
+		if c := val[i]; c < utf8.RuneSelf && !isLogfmtKeyByte(c) {
+			return true
+		}
+	}
+
+	return !utf8.ValidString(val)
+}
+
+// writeLogfmtValue writes val, quoted and escaped as in JSON strings if
+// needed.
+func writeLogfmtValue(w *writer, val string) {
+	if !logfmtNeedsQuoting(val) {
+		_, _ = w.WriteString(val)
+		return
+	}
+
+	_ = w.WriteByte('"')
+	for i := 0; i < len(val); {
+		c := val[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(val[i:])
+			if r == utf8.RuneError && size == 1 {
+				_, _ = w.WriteString(`\ufffd`)
+			} else {
+				_, _ = w.WriteString(val[i : i+size])
+			}
+			i += size
+			continue
+		}
+
+		switch c {
+		case '"', '\\':
+			_ = w.WriteByte('\\')
+			_ = w.WriteByte(c)
+		case '\n':
+			_, _ = w.WriteString(`\n`)
+		case '\r':
+			_, _ = w.WriteString(`\r`)
+		case '\t':
+			_, _ = w.WriteString(`\t`)
+		default:
+			if c < ' ' || c == 0x7f {
+				_, _ = w.WriteString(`\u00`)
+				_ = w.WriteByte(lowerhex[c>>4])
+				_ = w.WriteByte(lowerhex[c&0xF])
+			} else {
+				_ = w.WriteByte(c)
+			}
+		}
+		i++
+	}
+	_ = w.WriteByte('"')
+}
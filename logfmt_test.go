@@ -0,0 +1,86 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Logfmt(t *testing.T) {
+	t.Run("writes the header keys", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:         "app",
+			Output:       &buf,
+			OutputFormat: OutputLogfmt,
+			TimeFn: func() time.Time {
+				return time.Date(2026, 3, 4, 5, 6, 7, 891234000, time.UTC)
+			},
+		})
+
+		logger.Named("http").Info("request done", "status", 200, "path", "/a")
+		logger.Warn("")
+		logger.With("level", "fake").Error("collides", "msg", "other", "ts", 1)
+		logger.Info("collides again", "msg", "x", "_msg", "y", "__msg", "z")
+
+		assert.Equal(t,
+			`ts=2026-03-04T05:06:07.891234Z level=info module=app.http msg="request done" status=200 path=/a`+"\n"+
+				`ts=2026-03-04T05:06:07.891234Z level=warn module=app msg=""`+"\n"+
+				`ts=2026-03-04T05:06:07.891234Z level=error module=app msg=collides _level=fake _msg=other _ts=1`+"\n"+
+				`ts=2026-03-04T05:06:07.891234Z level=info module=app msg="collides again" ___msg=x _msg=y __msg=z`+"\n",
+			buf.String())
+	})
+
+	t.Run("quotes values and sanitizes keys", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:       &buf,
+			OutputFormat: OutputLogfmt,
+			DisableTime:  true,
+		})
+
+		logger.Info("values",
+			"multi", "line one\nline two",
+			"key with=space", "a=b",
+			"quote", Quote(`say "hi"`),
+			"empty", "",
+			"ctrl", "bell\a tab\t",
+			"utf8", "héllo",
+			"invalid", "\xffa",
+			"slice", []string{"a", "b"},
+			"err", errors.New("not found"),
+			"hex", Hex(255),
+		)
+
+		assert.Equal(t,
+			`level=info msg=values multi="line one\nline two" key_with_space="a=b" quote="say \"hi\"" empty="" `+
+				`ctrl="bell\u0007 tab\t" utf8=héllo invalid="\ufffda" slice="[\"a\", \"b\"]" err="not found" hex=0xff`+"\n",
+			buf.String())
+	})
+
+	t.Run("keeps stacktraces on the line", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:       &buf,
+			OutputFormat: OutputLogfmt,
+			DisableTime:  true,
+		})
+
+		logger.With("req", 1).Error("failed", "extra", CapturedStacktrace("frame 1\nframe 2"))
+		logger.Error("odd", "a")
+
+		assert.Equal(t,
+			`level=error msg=failed req=1 stacktrace="frame 1\nframe 2"`+"\n"+
+				`level=error msg=odd EXTRA_VALUE_AT_END=a`+"\n",
+			buf.String())
+	})
+}
@@ -92,6 +92,106 @@ const (
 	ForceColor
 )
 
+// OutputFormat selects how entries are encoded when written to the output.
+type OutputFormat uint8
+
+const (
+	// OutputPlain is the default human readable format.
+	OutputPlain OutputFormat = iota
+	// OutputJSON writes each entry as a JSON object on its own line.
+	OutputJSON
+	// OutputSyslog writes each entry as a syslog message, configured by
+	// LoggerOptions.Syslog. See also SyslogWriter.
+	OutputSyslog
+	// OutputLogfmt writes each entry as a line of logfmt, with the keys ts,
+	// level, module, msg and caller followed by the args.
+	OutputLogfmt
+	// OutputGELF writes each entry as a GELF 1.1 message for Graylog,
+	// configured by LoggerOptions.GELF. See also GELFWriter.
+	OutputGELF
+	// OutputOTLP writes each entry as an OpenTelemetry log record in the
+	// OTLP/JSON encoding, configured by LoggerOptions.OTLP. See also
+	// OTLPWriter.
+	OutputOTLP
+	// OutputECS writes each entry as a JSON object following the Elastic
+	// Common Schema, configured by LoggerOptions.ECS.
+	OutputECS
+)
+
+// OutputFormatFromString returns the OutputFormat with the given name, as
+// returned by OutputFormat.String, and whether the name is known.
+func OutputFormatFromString(s string) (OutputFormat, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "plain":
+		return OutputPlain, true
+	case "json":
+		return OutputJSON, true
+	case "syslog":
+		return OutputSyslog, true
+	case "logfmt":
+		return OutputLogfmt, true
+	case "gelf":
+		return OutputGELF, true
+	case "otlp":
+		return OutputOTLP, true
+	case "ecs":
+		return OutputECS, true
+	default:
+		return OutputPlain, false
+	}
+}
+
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputPlain:
+		return "plain"
+	case OutputJSON:
+		return "json"
+	case OutputSyslog:
+		return "syslog"
+	case OutputLogfmt:
+		return "logfmt"
+	case OutputGELF:
+		return "gelf"
+	case OutputOTLP:
+		return "otlp"
+	case OutputECS:
+		return "ecs"
+	default:
+		return "unknown"
+	}
+}
+
+// JSONFields sets the names of the fields written by the JSON format. An
+// empty name keeps the default, and a name of "-" drops the field.
+type JSONFields struct {
+	// Timestamp defaults to "@timestamp".
+	Timestamp string
+
+	// Level defaults to "@level".
+	Level string
+
+	// Message defaults to "@message".
+	Message string
+
+	// Module is the name of the logger, and defaults to "@module".
+	Module string
+
+	// Caller is the location of the logging call when IncludeLocation is
+	// set, and defaults to "@caller".
+	Caller string
+
+	// Stacktrace is a CapturedStacktrace passed as the last arg, and
+	// defaults to "stacktrace".
+	Stacktrace string
+
+	// Args, if set, nests the implied and call args of an entry in an object
+	// under this name, rather than writing them alongside the other fields.
+	// They then can't overwrite the fields above. The object is written with
+	// its keys sorted.
+	Args string
+}
+
 // SupportsColor is an optional interface that can be implemented by the output
 // value. If implemented and SupportsColor() returns true, then AutoColor will
 // enable colorization.
@@ -261,12 +361,45 @@ type LoggerOptions struct {
 	// log lines.
 	Mutex Locker
 
-	// Control if the output should be in JSON.
+	// Control if the output should be in JSON. This is the same as setting
+	// OutputFormat to OutputJSON.
 	JSONFormat bool
 
+	// The format to write entries in. Defaults to OutputPlain, unless
+	// JSONFormat is set.
+	OutputFormat OutputFormat
+
+	// Options for the OutputSyslog format. Defaults are used if nil.
+	Syslog *SyslogOptions
+
+	// Options for the OutputGELF format. Defaults are used if nil.
+	GELF *GELFOptions
+
+	// Options for the OutputOTLP format. Defaults are used if nil.
+	OTLP *OTLPOptions
+
+	// Options for the OutputECS format. Defaults are used if nil.
+	ECS *ECSOptions
+
 	// Control the escape switch of json.Encoder
 	JSONEscapeDisabled bool
 
+	// The names of the fields written by the JSON format. The defaults are
+	// used if nil.
+	JSONFields *JSONFields
+
+	// Redact sensitive values from args. Values wrapped with Sensitive are
+	// always redacted, but keys and the text of values are only matched if
+	// this is set.
+	Redact *RedactOptions
+
+	// Write errors with their type, their stack if they implement
+	// ErrorCallers, and the errors they wrap, rather than just their
+	// message. The JSON format writes an object with message, type, stack and
+	// causes fields, and the plain format an indented block. Errors which
+	// implement a marshaler are still written by it in the JSON format.
+	StructuredErrors bool
+
 	// Include file and line information in each log line
 	IncludeLocation bool
 
@@ -299,8 +432,26 @@ type LoggerOptions struct {
 	// should not be logged.
 	// This is useful when interacting with a system that you wish to suppress the log
 	// message for (because it's too noisy, etc)
+	//
+	// It is given the args as they were passed to the logger, before any
+	// redaction or grouping, and is called with the output locked, so calls
+	// to it are never concurrent.
 	Exclude func(level Level, msg string, args ...any) bool
 
+	// Sampling optionally limits how often entries with the same level and
+	// message are written, see SamplingOptions. It is applied after Exclude
+	// and is shared by all subloggers.
+	Sampling *SamplingOptions
+
+	// CollapseRepeats enables holding back entries that are identical to the
+	// one before them, in level, name, message and args. The repeats are
+	// written as a single entry, with a RepeatedKey arg giving the count,
+	// once a different entry is logged or CollapseRepeats has passed since
+	// the first repeat. ResetOutput and ResetOutputWithFlush write a held
+	// entry to the old output, and so does the Flush method of the logger,
+	// which implements Flushable. Zero disables collapsing.
+	CollapseRepeats time.Duration
+
 	// IndependentLevels causes subloggers to be created with an independent
 	// copy of this logger's level. This means that using SetLevel on this
 	// logger will not affect any subloggers, and SetLevel on any subloggers
@@ -325,6 +476,13 @@ type LoggerOptions struct {
 	// c.GetLevel() => Warn
 	SyncParentLevel bool
 
+	// LevelDirectives optionally assigns levels to this logger and its
+	// subloggers based on their names, e.g. "raft=debug,http=warn". A
+	// logger matched by a directive uses that level instead of Level and
+	// ignores SetLevel. The directives are shared, not copied, so calling
+	// Set on them changes the levels of all loggers using them.
+	LevelDirectives *LevelDirectives
+
 	// SubloggerHook registers a function that is called when a sublogger via
 	// Named, With, or ResetNamed is created. If defined, the function is passed
 	// the newly created Logger and the returned Logger is returned from the
@@ -372,6 +530,39 @@ type SinkAdapter interface {
 	Accept(name string, level Level, msg string, args ...any)
 }
 
+// SinkOptionsRegisterer is implemented by InterceptLoggers which can send a
+// sink only some of their entries. The InterceptLogger returned by
+// NewInterceptLogger implements it:
+//
+//	intercept.(hclog.SinkOptionsRegisterer).RegisterSinkWithOptions(sink, &hclog.SinkOptions{
+//		Level: hclog.Warn,
+//	})
+type SinkOptionsRegisterer interface {
+	// RegisterSinkWithOptions adds a SinkAdapter to the InterceptLogger which
+	// only receives the entries selected by opts. Registering a sink again
+	// replaces its options, and DeregisterSink removes it.
+	RegisterSinkWithOptions(sink SinkAdapter, opts *SinkOptions)
+}
+
+// SinkOptions selects the entries sent to a SinkAdapter. Entries which a
+// sink doesn't want are dropped before their args are copied for it, and
+// when no sink wants an entry the InterceptLogger does no work for sinks.
+type SinkOptions struct {
+	// Level is the minimum level of entries sent to the sink. By default
+	// entries at every level are sent, and the sink may check them itself.
+	Level Level
+
+	// Modules limits the entries sent to the sink to those from loggers with
+	// one of these names, or with a name descending from one, so "raft"
+	// selects both "raft" and "raft.snapshot".
+	Modules []string
+
+	// Filter, if set, is called with each entry which passes Level and
+	// Modules, and the entry is sent to the sink only if it returns true.
+	// The args include the logger's implied args, as given to Accept.
+	Filter func(name string, level Level, msg string, args ...any) bool
+}
+
 // Flushable represents a method for flushing an output buffer. It can be used
 // if Resetting the log to use a new output, in order to flush the writes to
 // the existing output beforehand.
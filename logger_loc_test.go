@@ -6,6 +6,7 @@ package hclog
 import (
 	"bytes"
 	"fmt"
+	"log/slog"
 	"runtime"
 	"strings"
 	"testing"
@@ -72,4 +73,30 @@ func TestLoggerLoc(t *testing.T) {
 			rest)
 	})
 
+	t.Run("includes the caller location of slog records", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:            "test",
+			Output:          &buf,
+			IncludeLocation: true,
+		})
+
+		sl := slog.New(NewSlogHandler(logger))
+
+		_, _, line, _ := runtime.Caller(0)
+
+		sl.Info("this is test", "who", "programmer")
+
+		str := buf.String()
+		dataIdx := strings.IndexByte(str, ' ')
+		rest := str[dataIdx+1:]
+
+		assert.Equal(t,
+			fmt.Sprintf(
+				"[INFO]  go-hclog/logger_loc_test.go:%d: test: this is test: who=programmer\n",
+				line+2,
+			),
+			rest)
+	})
 }
@@ -1254,6 +1254,61 @@ func TestLogger_JSON(t *testing.T) {
 		assert.Equal(t, "this is test and use > < &", raw["@message"])
 	})
 
+	t.Run("renames and drops envelope fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:            "test",
+			Output:          &buf,
+			JSONFormat:      true,
+			IncludeLocation: true,
+			JSONFields: &JSONFields{
+				Timestamp:  "-",
+				Level:      "severity",
+				Message:    "msg",
+				Module:     "logger",
+				Caller:     "-",
+				Stacktrace: "stack",
+			},
+		})
+
+		logger.Error("this is test", "who", "programmer", CapturedStacktrace("frames"))
+
+		var raw map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, map[string]any{
+			"severity": "error",
+			"msg":      "this is test",
+			"logger":   "test",
+			"who":      "programmer",
+			"stack":    "frames",
+		}, raw)
+	})
+
+	t.Run("nests args under a key", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:        "test",
+			Output:      &buf,
+			JSONFormat:  true,
+			DisableTime: true,
+			JSONFields: &JSONFields{
+				Args: "fields",
+			},
+		})
+
+		logger.With("@module", "implied").Info("this is test", "@message", "overwritten", "extra")
+		logger.Info("no args")
+
+		assert.Equal(t,
+			`{"@level":"info","@message":"this is test","@module":"test","fields":{"@message":"overwritten","@module":"implied","EXTRA_VALUE_AT_END":"extra"}}`+"\n"+
+				`{"@level":"info","@message":"no args","@module":"test"}`+"\n",
+			buf.String())
+	})
 }
 
 type customErrJSON struct {
@@ -19,6 +19,8 @@ type nullLogger struct{}
 
 func (l *nullLogger) Log(level Level, msg string, args ...any) {}
 
+func (l *nullLogger) LogFields(level Level, msg string, fields ...Field) {}
+
 func (l *nullLogger) Trace(msg string, args ...any) {}
 
 func (l *nullLogger) Debug(msg string, args ...any) {}
@@ -43,6 +45,8 @@ func (l *nullLogger) ImpliedArgs() []any { return []any{} }
 
 func (l *nullLogger) With(args ...any) Logger { return l }
 
+func (l *nullLogger) WithGroup(name string) Logger { return l }
+
 func (l *nullLogger) Name() string { return "" }
 
 func (l *nullLogger) Named(name string) Logger { return l }
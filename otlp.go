@@ -0,0 +1,318 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// OTLPTraceIDKey is the key of an arg holding the trace id of an entry,
+	// as 32 hex digits or a [16]byte. With OutputOTLP it is written as the
+	// traceId of the log record rather than as an attribute.
+	OTLPTraceIDKey = "trace_id"
+
+	// OTLPSpanIDKey is the key of an arg holding the span id of an entry, as
+	// 16 hex digits or an [8]byte. With OutputOTLP it is written as the
+	// spanId of the log record rather than as an attribute.
+	OTLPSpanIDKey = "span_id"
+)
+
+// OTLPOptions configures the OutputOTLP format.
+type OTLPOptions struct {
+	// ServiceName is written as the service.name resource attribute.
+	// Defaults to the program name.
+	ServiceName string
+
+	// ResourceAttributes are additional attributes describing the resource
+	// producing the logs, such as service.version.
+	ResourceAttributes map[string]string
+}
+
+// otlpFormat is the resolved OTLPOptions for a logger.
+type otlpFormat struct {
+	resource otlpResource
+}
+
+func newOTLPFormat(opts *OTLPOptions) *otlpFormat {
+	if opts == nil {
+		opts = &OTLPOptions{}
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = filepath.Base(os.Args[0])
+	}
+
+	attrs := []otlpKeyValue{{Key: "service.name", Value: otlpString(serviceName)}}
+
+	keys := make([]string, 0, len(opts.ResourceAttributes))
+	for k := range opts.ResourceAttributes {
+		if k != "service.name" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		attrs = append(attrs, otlpKeyValue{Key: k, Value: otlpString(opts.ResourceAttributes[k])})
+	}
+
+	return &otlpFormat{
+		resource: otlpResource{Attributes: attrs},
+	}
+}
+
+// The OTLP/JSON encoding of the protobuf messages, limited to the fields
+// used here.
+type (
+	otlpRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name,omitempty"`
+	}
+
+	otlpLogRecord struct {
+		TimeUnixNano         string         `json:"timeUnixNano,omitempty"`
+		ObservedTimeUnixNano string         `json:"observedTimeUnixNano,omitempty"`
+		SeverityNumber       int            `json:"severityNumber,omitempty"`
+		SeverityText         string         `json:"severityText,omitempty"`
+		Body                 otlpAnyValue   `json:"body"`
+		Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+		TraceID              string         `json:"traceId,omitempty"`
+		SpanID               string         `json:"spanId,omitempty"`
+	}
+
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+
+	otlpAnyValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		BoolValue   *bool           `json:"boolValue,omitempty"`
+		IntValue    string          `json:"intValue,omitempty"`
+		DoubleValue any             `json:"doubleValue,omitempty"`
+		BytesValue  string          `json:"bytesValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+		KvlistValue *otlpKvlist     `json:"kvlistValue,omitempty"`
+	}
+
+	otlpArrayValue struct {
+		Values []otlpAnyValue `json:"values"`
+	}
+
+	otlpKvlist struct {
+		Values []otlpKeyValue `json:"values"`
+	}
+)
+
+func otlpString(s string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &s}
+}
+
+// otlpSeverity maps a level to an OpenTelemetry severity number and text.
+func otlpSeverity(level Level) (int, string) {
+	switch level {
+	case Trace:
+		return 1, "TRACE"
+	case Debug:
+		return 5, "DEBUG"
+	case Info:
+		return 9, "INFO"
+	case Warn:
+		return 13, "WARN"
+	case Error:
+		return 17, "ERROR"
+	default:
+		return 0, ""
+	}
+}
+
+// logOTLP writes the entry as an OTLP/JSON ExportLogsServiceRequest holding a
+// single log record, which is the form read by the OpenTelemetry Collector's
+// otlpjsonfile receiver and accepted by OTLP/HTTP endpoints. The name of the
+// logger is used as the instrumentation scope. OTLPWriter combines these
+// into batches.
+func (l *intLogger) logOTLP(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	pairs, stacktrace := splitArgs(implied, args)
+
+	rec := otlpLogRecord{
+		Body: otlpString(msg),
+	}
+	rec.SeverityNumber, rec.SeverityText = otlpSeverity(level)
+
+	if !l.disableTime && !t.IsZero() {
+		rec.TimeUnixNano = strconv.FormatInt(t.UnixNano(), 10)
+		rec.ObservedTimeUnixNano = rec.TimeUnixNano
+	}
+
+	if l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			rec.Attributes = append(rec.Attributes,
+				otlpKeyValue{Key: "code.file.path", Value: otlpString(file)},
+				otlpKeyValue{Key: "code.line.number", Value: otlpAnyValue{IntValue: strconv.Itoa(line)}},
+			)
+		}
+	}
+
+	for i := 0; i < len(pairs); i += 2 {
+		key := argKey(pairs[i])
+
+		switch key {
+		case OTLPTraceIDKey:
+			if id, ok := otlpID(pairs[i+1], 16); ok {
+				rec.TraceID = id
+				continue
+			}
+		case OTLPSpanIDKey:
+			if id, ok := otlpID(pairs[i+1], 8); ok {
+				rec.SpanID = id
+				continue
+			}
+		}
+
+		rec.Attributes = append(rec.Attributes, otlpKeyValue{
+			Key:   key,
+			Value: l.otlpValue(pairs[i+1]),
+		})
+	}
+
+	if stacktrace != "" {
+		rec.Attributes = append(rec.Attributes, otlpKeyValue{
+			Key:   "exception.stacktrace",
+			Value: otlpString(string(stacktrace)),
+		})
+	}
+
+	req := otlpRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: l.otlp.resource,
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: name},
+				LogRecords: []otlpLogRecord{rec},
+			}},
+		}},
+	}
+
+	encoder := json.NewEncoder(l.writer)
+	encoder.SetEscapeHTML(l.jsonEscapeEnabled)
+	_ = encoder.Encode(req)
+}
+
+// otlpID returns v as the hex encoding of an id of size bytes, if it is one.
+func otlpID(v any, size int) (string, bool) {
+	switch st := v.(type) {
+	case string:
+		if len(st) != size*2 {
+			return "", false
+		}
+		if _, err := hex.DecodeString(st); err != nil {
+			return "", false
+		}
+		return strings.ToLower(st), true
+	case [16]byte:
+		return hex.EncodeToString(st[:]), size == 16
+	case [8]byte:
+		return hex.EncodeToString(st[:]), size == 8
+	default:
+		return "", false
+	}
+}
+
+// otlpValue converts an arg value to an AnyValue, keeping the type of basic
+// values and the structure of slices and maps.
+func (l *intLogger) otlpValue(v any) otlpAnyValue {
+	switch st := v.(type) {
+	case nil:
+		return otlpAnyValue{}
+	case string:
+		return otlpString(st)
+	case Quote:
+		return otlpString(string(st))
+	case bool:
+		return otlpAnyValue{BoolValue: &st}
+	case []byte:
+		return otlpAnyValue{BytesValue: base64.StdEncoding.EncodeToString(st)}
+	case error:
+		return otlpString(st.Error())
+	case fmt.Stringer:
+		return otlpString(st.String())
+	case Hex, Octal, Binary, Format:
+		val, _ := l.plainValue(st)
+		return otlpString(val)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return otlpAnyValue{IntValue: strconv.FormatInt(rv.Int(), 10)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return otlpAnyValue{IntValue: strconv.FormatUint(u, 10)}
+		}
+		return otlpString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		switch {
+		case math.IsNaN(f):
+			return otlpAnyValue{DoubleValue: "NaN"}
+		case math.IsInf(f, 1):
+			return otlpAnyValue{DoubleValue: "Infinity"}
+		case math.IsInf(f, -1):
+			return otlpAnyValue{DoubleValue: "-Infinity"}
+		default:
+			return otlpAnyValue{DoubleValue: f}
+		}
+	case reflect.Slice, reflect.Array:
+		arr := &otlpArrayValue{Values: make([]otlpAnyValue, 0, rv.Len())}
+		for i := 0; i < rv.Len(); i++ {
+			arr.Values = append(arr.Values, l.otlpValue(rv.Index(i).Interface()))
+		}
+		return otlpAnyValue{ArrayValue: arr}
+	case reflect.Map:
+		kv := &otlpKvlist{Values: make([]otlpKeyValue, 0, rv.Len())}
+		iter := rv.MapRange()
+		for iter.Next() {
+			kv.Values = append(kv.Values, otlpKeyValue{
+				Key:   fmt.Sprint(iter.Key().Interface()),
+				Value: l.otlpValue(iter.Value().Interface()),
+			})
+		}
+		sort.Slice(kv.Values, func(i, j int) bool {
+			return kv.Values[i].Key < kv.Values[j].Key
+		})
+		return otlpAnyValue{KvlistValue: kv}
+	default:
+		val, _ := l.plainValue(v)
+		return otlpString(val)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_OTLP(t *testing.T) {
+	t.Run("writes a log record", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:         "app",
+			Output:       &buf,
+			OutputFormat: OutputOTLP,
+			OTLP: &OTLPOptions{
+				ServiceName:        "billing",
+				ResourceAttributes: map[string]string{"service.version": "1.2.3"},
+			},
+			TimeFn: func() time.Time {
+				return time.Unix(1772600767, 891234567)
+			},
+		})
+
+		logger.Named("http").
+			With("trace_id", "4BF92F3577B34DA6A3CE929D0E0E4736", "span_id", [8]byte{0, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}).
+			Warn("slow request",
+				"status", 503,
+				"ratio", 0.5,
+				"nan", math.NaN(),
+				"retry", true,
+				"error", errors.New("timeout"),
+				"tags", []string{"a", "b"},
+				"elapsed", 1500*time.Millisecond,
+				"nothing", nil,
+				CapturedStacktrace("stack"),
+			)
+
+		assert.JSONEq(t, `{
+			"resourceLogs": [{
+				"resource": {
+					"attributes": [
+						{"key": "service.name", "value": {"stringValue": "billing"}},
+						{"key": "service.version", "value": {"stringValue": "1.2.3"}}
+					]
+				},
+				"scopeLogs": [{
+					"scope": {"name": "app.http"},
+					"logRecords": [{
+						"timeUnixNano": "1772600767891234567",
+						"observedTimeUnixNano": "1772600767891234567",
+						"severityNumber": 13,
+						"severityText": "WARN",
+						"body": {"stringValue": "slow request"},
+						"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+						"spanId": "00f067aa0ba902b7",
+						"attributes": [
+							{"key": "status", "value": {"intValue": "503"}},
+							{"key": "ratio", "value": {"doubleValue": 0.5}},
+							{"key": "nan", "value": {"doubleValue": "NaN"}},
+							{"key": "retry", "value": {"boolValue": true}},
+							{"key": "error", "value": {"stringValue": "timeout"}},
+							{"key": "tags", "value": {"arrayValue": {"values": [{"stringValue": "a"}, {"stringValue": "b"}]}}},
+							{"key": "elapsed", "value": {"stringValue": "1.5s"}},
+							{"key": "nothing", "value": {}},
+							{"key": "exception.stacktrace", "value": {"stringValue": "stack"}}
+						]
+					}]
+				}]
+			}]
+		}`, buf.String())
+	})
+
+	t.Run("keeps invalid ids as attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:       &buf,
+			OutputFormat: OutputOTLP,
+			DisableTime:  true,
+		})
+
+		logger.Info("hello", "trace_id", "abc", "span_id", 7)
+
+		var req otlpRequest
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &req))
+
+		rec := req.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+		assert.Equal(t, "", req.ResourceLogs[0].ScopeLogs[0].Scope.Name)
+		assert.Equal(t, "", rec.TimeUnixNano)
+		assert.Equal(t, "", rec.TraceID)
+		assert.Equal(t, "", rec.SpanID)
+		assert.Equal(t, []otlpKeyValue{
+			{Key: "trace_id", Value: otlpString("abc")},
+			{Key: "span_id", Value: otlpAnyValue{IntValue: "7"}},
+		}, rec.Attributes)
+	})
+}
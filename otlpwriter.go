@@ -0,0 +1,347 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultOTLPEndpoint is the OTLP/HTTP logs endpoint of a collector
+	// running locally with the default configuration.
+	DefaultOTLPEndpoint = "http://localhost:4318/v1/logs"
+
+	// DefaultOTLPBatchSize is the number of entries an OTLPWriter sends in
+	// one request if no other is given.
+	DefaultOTLPBatchSize = 512
+
+	// DefaultOTLPFlushInterval is how often an OTLPWriter sends the entries
+	// it has if no other interval is given.
+	DefaultOTLPFlushInterval = time.Second
+)
+
+// OTLPWriterOptions can be used to configure a new OTLPWriter.
+type OTLPWriterOptions struct {
+	// Endpoint is the URL requests are posted to. Defaults to
+	// DefaultOTLPEndpoint.
+	Endpoint string
+
+	// Headers are added to each request, such as for authentication.
+	Headers map[string]string
+
+	// Client is used to send requests. Defaults to a client with a 10 second
+	// timeout.
+	Client *http.Client
+
+	// BatchSize is the number of entries to send in one request. Up to four
+	// batches are held while the endpoint is slow, after which entries are
+	// dropped. Defaults to DefaultOTLPBatchSize.
+	BatchSize int
+
+	// FlushInterval is the longest an entry waits to be sent. Defaults to
+	// DefaultOTLPFlushInterval.
+	FlushInterval time.Duration
+}
+
+// Make sure that OTLPWriter is Flushable
+var _ Flushable = &OTLPWriter{}
+
+// OTLPWriter collects entries written in the OutputOTLP format and posts them
+// in batches to an OTLP/HTTP logs endpoint, such as that of an OpenTelemetry
+// Collector, from a background goroutine. The records of a batch which share
+// a resource and scope are sent together under a single one of each.
+//
+// Sending errors are returned by the next call to Flush, and the entries in a
+// failed request are not retried. Call Close on shutdown to send any
+// remaining entries.
+type OTLPWriter struct {
+	endpoint  string
+	headers   map[string]string
+	client    *http.Client
+	batchSize int
+
+	mu      sync.Mutex
+	pending []otlpPendingRecord
+	err     error
+	dropped uint64
+	closed  bool
+
+	// sendMu serializes requests so that batches arrive in order.
+	sendMu sync.Mutex
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewOTLPWriter returns an OTLPWriter and starts its background goroutine.
+func NewOTLPWriter(opts *OTLPWriterOptions) *OTLPWriter {
+	if opts == nil {
+		opts = &OTLPWriterOptions{}
+	}
+
+	w := &OTLPWriter{
+		endpoint:  opts.Endpoint,
+		headers:   opts.Headers,
+		client:    opts.Client,
+		batchSize: opts.BatchSize,
+		full:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	if w.endpoint == "" {
+		w.endpoint = DefaultOTLPEndpoint
+	}
+	if w.client == nil {
+		w.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = DefaultOTLPBatchSize
+	}
+
+	interval := opts.FlushInterval
+	if interval <= 0 {
+		interval = DefaultOTLPFlushInterval
+	}
+
+	go w.run(interval)
+
+	return w
+}
+
+// otlpPendingRecord is a log record waiting to be sent, with the resource
+// and scope of the request it was written in.
+type otlpPendingRecord struct {
+	resource string
+	scope    string
+	record   json.RawMessage
+}
+
+// Write adds the log records of an entry to the next batch. p must be an
+// OTLP/JSON ExportLogsServiceRequest, as written by OutputOTLP.
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	var req struct {
+		ResourceLogs []struct {
+			Resource  json.RawMessage `json:"resource"`
+			ScopeLogs []struct {
+				Scope      json.RawMessage   `json:"scope"`
+				LogRecords []json.RawMessage `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(p, &req); err != nil {
+		return 0, fmt.Errorf("not an OTLP/JSON logs request: %w", err)
+	}
+
+	var records []otlpPendingRecord
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			for _, rec := range sl.LogRecords {
+				records = append(records, otlpPendingRecord{
+					resource: string(rl.Resource),
+					scope:    string(sl.Scope),
+					record:   rec,
+				})
+			}
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending)+len(records) > 4*w.batchSize {
+		w.dropped += uint64(len(records))
+		return len(p), nil
+	}
+
+	w.pending = append(w.pending, records...)
+
+	if len(w.pending) >= w.batchSize {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *OTLPWriter) run(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.full:
+		case <-ticker.C:
+		}
+
+		if err := w.send(); err != nil {
+			w.mu.Lock()
+			if w.err == nil {
+				w.err = err
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// send posts everything pending, one batch at a time, returning the first
+// error.
+func (w *OTLPWriter) send() error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	var errs []error
+	for {
+		w.mu.Lock()
+		n := min(len(w.pending), w.batchSize)
+		batch := w.pending[:n:n]
+		w.pending = w.pending[n:]
+		w.mu.Unlock()
+
+		if n == 0 {
+			return errors.Join(errs...)
+		}
+
+		if err := w.post(batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
+
+// otlpBatch is a request holding the log records of a batch, with those of
+// the same resource and scope sharing a single resourceLogs and scopeLogs.
+type (
+	otlpBatch struct {
+		ResourceLogs []*otlpBatchResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpBatchResourceLogs struct {
+		Resource  json.RawMessage       `json:"resource,omitempty"`
+		ScopeLogs []*otlpBatchScopeLogs `json:"scopeLogs"`
+
+		scopes map[string]*otlpBatchScopeLogs
+	}
+
+	otlpBatchScopeLogs struct {
+		Scope      json.RawMessage   `json:"scope,omitempty"`
+		LogRecords []json.RawMessage `json:"logRecords"`
+	}
+)
+
+// newOTLPBatch returns a request for records, keeping the order in which
+// each resource, scope and record first appears.
+func newOTLPBatch(records []otlpPendingRecord) otlpBatch {
+	var batch otlpBatch
+	resources := make(map[string]*otlpBatchResourceLogs)
+
+	for _, r := range records {
+		rl, ok := resources[r.resource]
+		if !ok {
+			rl = &otlpBatchResourceLogs{
+				Resource: json.RawMessage(r.resource),
+				scopes:   make(map[string]*otlpBatchScopeLogs),
+			}
+			resources[r.resource] = rl
+			batch.ResourceLogs = append(batch.ResourceLogs, rl)
+		}
+
+		sl, ok := rl.scopes[r.scope]
+		if !ok {
+			sl = &otlpBatchScopeLogs{Scope: json.RawMessage(r.scope)}
+			rl.scopes[r.scope] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+
+		sl.LogRecords = append(sl.LogRecords, r.record)
+	}
+
+	return batch
+}
+
+func (w *OTLPWriter) post(batch []otlpPendingRecord) error {
+	body, err := json.Marshal(newOTLPBatch(batch))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("OTLP export of %d entries failed: %s", len(batch), resp.Status)
+	}
+
+	return nil
+}
+
+// Flush sends everything written so far, and returns the first error
+// encountered sending since the last call to Flush.
+func (w *OTLPWriter) Flush() error {
+	err := w.send()
+
+	w.mu.Lock()
+	if w.err != nil {
+		err = errors.Join(w.err, err)
+		w.err = nil
+	}
+	w.mu.Unlock()
+
+	return err
+}
+
+// Close stops the background goroutine and sends any remaining entries.
+// Entries written after Close are sent on the next call to Flush.
+func (w *OTLPWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return w.Flush()
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.done
+
+	return w.Flush()
+}
+
+// Dropped returns the number of entries discarded because too many were
+// waiting to be sent.
+func (w *OTLPWriter) Dropped() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dropped
+}
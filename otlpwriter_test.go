@@ -0,0 +1,217 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// otlpCollector records the requests posted to it.
+type otlpCollector struct {
+	mu       sync.Mutex
+	status   int
+	requests []otlpRequest
+	headers  []http.Header
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req otlpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, r.Header)
+
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+}
+
+func (c *otlpCollector) messages() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var batches [][]string
+	for _, req := range c.requests {
+		var msgs []string
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				for _, rec := range sl.LogRecords {
+					msgs = append(msgs, *rec.Body.StringValue)
+				}
+			}
+		}
+		batches = append(batches, msgs)
+	}
+
+	return batches
+}
+
+func TestOTLPWriter(t *testing.T) {
+	t.Run("posts batches", func(t *testing.T) {
+		var collector otlpCollector
+		srv := httptest.NewServer(&collector)
+		defer srv.Close()
+
+		w := NewOTLPWriter(&OTLPWriterOptions{
+			Endpoint:      srv.URL,
+			Headers:       map[string]string{"Authorization": "Bearer token"},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+		})
+		defer w.Close()
+
+		logger := New(&LoggerOptions{
+			Output:       w,
+			OutputFormat: OutputOTLP,
+		})
+
+		logger.Info("one")
+		logger.Info("two")
+
+		// A full batch is sent without waiting for the interval.
+		assert.Eventually(t, func() bool {
+			return len(collector.messages()) == 1
+		}, 2*time.Second, 5*time.Millisecond)
+
+		logger.Info("three")
+		require.NoError(t, w.Flush())
+
+		assert.Equal(t, [][]string{{"one", "two"}, {"three"}}, collector.messages())
+		assert.Equal(t, "Bearer token", collector.headers[0].Get("Authorization"))
+		assert.Equal(t, "application/json", collector.headers[0].Get("Content-Type"))
+	})
+
+	t.Run("merges the records of a batch by resource and scope", func(t *testing.T) {
+		var collector otlpCollector
+		srv := httptest.NewServer(&collector)
+		defer srv.Close()
+
+		w := NewOTLPWriter(&OTLPWriterOptions{
+			Endpoint:      srv.URL,
+			FlushInterval: time.Hour,
+		})
+		defer w.Close()
+
+		logger := New(&LoggerOptions{
+			Output:       w,
+			OutputFormat: OutputOTLP,
+			OTLP:         &OTLPOptions{ServiceName: "app"},
+		})
+		other := New(&LoggerOptions{
+			Output:       w,
+			OutputFormat: OutputOTLP,
+			OTLP:         &OTLPOptions{ServiceName: "other"},
+		})
+
+		logger.Named("a").Info("one")
+		logger.Named("b").Info("two")
+		other.Named("a").Info("three")
+		logger.Named("a").Info("four")
+
+		require.NoError(t, w.Flush())
+		require.Len(t, collector.requests, 1)
+
+		type scope struct {
+			name string
+			msgs []string
+		}
+
+		var resources [][]scope
+		for _, rl := range collector.requests[0].ResourceLogs {
+			var scopes []scope
+			for _, sl := range rl.ScopeLogs {
+				s := scope{name: sl.Scope.Name}
+				for _, rec := range sl.LogRecords {
+					s.msgs = append(s.msgs, *rec.Body.StringValue)
+				}
+				scopes = append(scopes, s)
+			}
+			resources = append(resources, scopes)
+		}
+
+		assert.Equal(t, [][]scope{
+			{{"a", []string{"one", "four"}}, {"b", []string{"two"}}},
+			{{"a", []string{"three"}}},
+		}, resources)
+		assert.Equal(t, "app", *collector.requests[0].ResourceLogs[0].Resource.Attributes[0].Value.StringValue)
+	})
+
+	t.Run("sends on the interval", func(t *testing.T) {
+		var collector otlpCollector
+		srv := httptest.NewServer(&collector)
+		defer srv.Close()
+
+		w := NewOTLPWriter(&OTLPWriterOptions{
+			Endpoint:      srv.URL,
+			FlushInterval: 10 * time.Millisecond,
+		})
+		defer w.Close()
+
+		New(&LoggerOptions{Output: w, OutputFormat: OutputOTLP}).Info("tick")
+
+		assert.Eventually(t, func() bool {
+			return len(collector.messages()) == 1
+		}, 2*time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("reports failed requests from Flush", func(t *testing.T) {
+		collector := otlpCollector{status: http.StatusServiceUnavailable}
+		srv := httptest.NewServer(&collector)
+		defer srv.Close()
+
+		w := NewOTLPWriter(&OTLPWriterOptions{
+			Endpoint:      srv.URL,
+			FlushInterval: time.Hour,
+		})
+
+		New(&LoggerOptions{Output: w, OutputFormat: OutputOTLP}).Info("lost")
+
+		assert.EqualError(t, w.Flush(), "OTLP export of 1 entries failed: 503 Service Unavailable")
+		assert.NoError(t, w.Flush())
+		assert.NoError(t, w.Close())
+	})
+
+	t.Run("drops entries beyond four batches", func(t *testing.T) {
+		w := NewOTLPWriter(&OTLPWriterOptions{
+			Endpoint:      "http://127.0.0.1:0",
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+		})
+
+		// Hold up the background goroutine so nothing is sent.
+		w.sendMu.Lock()
+
+		logger := New(&LoggerOptions{Output: w, OutputFormat: OutputOTLP})
+		for range 6 {
+			logger.Info("entry")
+		}
+
+		assert.Equal(t, uint64(2), w.Dropped())
+		w.sendMu.Unlock()
+
+		_ = w.Close()
+	})
+
+	t.Run("rejects other formats", func(t *testing.T) {
+		w := NewOTLPWriter(nil)
+		defer w.Close()
+
+		_, err := w.Write([]byte("[INFO]  hello\n"))
+		assert.Error(t, err)
+	})
+}
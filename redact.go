@@ -0,0 +1,373 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RedactedText is written in place of a redacted value, unless a
+// PseudonymKey is set.
+const RedactedText = "[REDACTED]"
+
+// DefaultRedactKeys are the key patterns used if RedactOptions.Keys is nil.
+var DefaultRedactKeys = []string{"password", "token", "secret", "authorization"}
+
+// DefaultRedactMatchers are the value matchers used if
+// RedactOptions.Matchers is nil.
+var DefaultRedactMatchers = []ValueMatcher{JWTMatcher, AWSAccessKeyMatcher, CreditCardMatcher}
+
+// Redacted is a value which is never written as is. It's created by
+// Sensitive, and formats as RedactedText in every format and with fmt, even
+// if the logger has no RedactOptions.
+type Redacted struct {
+	value any
+}
+
+// Sensitive marks a value as sensitive:
+//
+//	logger.Info("connecting", "dsn", hclog.Sensitive(dsn))
+//
+// The value is written as RedactedText, or as a pseudonym if the logger has a
+// RedactOptions.PseudonymKey.
+func Sensitive(v any) Redacted {
+	return Redacted{value: v}
+}
+
+func (r Redacted) String() string {
+	return RedactedText
+}
+
+// Format implements fmt.Formatter so that no verb reveals the value.
+func (r Redacted) Format(f fmt.State, verb rune) {
+	_, _ = io.WriteString(f, RedactedText)
+}
+
+func (r Redacted) MarshalText() ([]byte, error) {
+	return []byte(RedactedText), nil
+}
+
+// LogValue implements slog.LogValuer.
+func (r Redacted) LogValue() slog.Value {
+	return slog.StringValue(RedactedText)
+}
+
+// A ValueMatcher finds secrets within the text of a value, returning the start
+// and end of up to n matches, or all of them if n is negative.
+// *regexp.Regexp is a ValueMatcher.
+type ValueMatcher interface {
+	FindAllStringIndex(s string, n int) [][]int
+}
+
+var (
+	// JWTMatcher matches JSON Web Tokens.
+	JWTMatcher ValueMatcher = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+	// AWSAccessKeyMatcher matches AWS access key IDs.
+	AWSAccessKeyMatcher ValueMatcher = regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)
+
+	// CreditCardMatcher matches payment card numbers of 13 to 19 digits,
+	// optionally separated by spaces or dashes, which pass the Luhn check.
+	CreditCardMatcher ValueMatcher = creditCardMatcher{}
+)
+
+var creditCardCandidate = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+type creditCardMatcher struct{}
+
+func (creditCardMatcher) FindAllStringIndex(s string, n int) [][]int {
+	var matches [][]int
+
+	for _, m := range creditCardCandidate.FindAllStringIndex(s, -1) {
+		if n >= 0 && len(matches) == n {
+			break
+		}
+
+		if luhnValid(s[m[0]:m[1]]) {
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// luhnValid reports whether the digits of s pass the Luhn check, ignoring
+// any other characters.
+func luhnValid(s string) bool {
+	var sum, n int
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		n++
+	}
+
+	return n > 0 && sum%10 == 0
+}
+
+// RedactOptions configures the redaction of sensitive values. Redaction
+// applies to the args of every entry and to the implied args given to With,
+// before they're written or passed to the sinks of an InterceptLogger.
+type RedactOptions struct {
+	// Keys are patterns matched against the key of each arg. The value of an
+	// arg whose key contains one of them, ignoring case, is redacted.
+	// DefaultRedactKeys is used if nil.
+	Keys []string
+
+	// Matchers find secrets within the text of string, error, fmt.Stringer
+	// and Fmt values. Only the matching parts of a value are redacted.
+	// DefaultRedactMatchers is used if nil.
+	Matchers []ValueMatcher
+
+	// PseudonymKey, if set, replaces each redacted value with a pseudonym
+	// derived from it using HMAC-SHA256 with this key, rather than with
+	// RedactedText. Equal values get the same pseudonym, so that entries can
+	// be correlated without revealing the values.
+	PseudonymKey []byte
+}
+
+// redactor is the resolved RedactOptions for a logger. A nil redactor
+// redacts nothing.
+type redactor struct {
+	keys         []string
+	matchers     []ValueMatcher
+	pseudonymKey []byte
+}
+
+func newRedactor(opts *RedactOptions) *redactor {
+	if opts == nil {
+		return nil
+	}
+
+	r := &redactor{
+		keys:         opts.Keys,
+		matchers:     opts.Matchers,
+		pseudonymKey: opts.PseudonymKey,
+	}
+
+	if r.keys == nil {
+		r.keys = DefaultRedactKeys
+	}
+
+	if r.matchers == nil {
+		r.matchers = DefaultRedactMatchers
+	}
+
+	return r
+}
+
+// redact returns args with sensitive values replaced. args is returned as is
+// if nothing is replaced, and is never modified.
+func (r *redactor) redact(args []any) []any {
+	if r == nil {
+		return args
+	}
+
+	var redacted []any
+
+	for i := 0; i < len(args); {
+		key, val, next := nextArg(args, i)
+
+		// The args of a group are redacted individually, unless its name
+		// matches.
+		if group, ok := asGroup(val); ok && next == i+1 && !r.matchKey(key) {
+			if g := r.redact(group); len(g) > 0 && &g[0] != &group[0] {
+				if redacted == nil {
+					redacted = slices.Clone(args)
+				}
+				redacted[i] = Group(key, g...)
+			}
+
+			i = next
+			continue
+		}
+
+		// A value which resolves itself is redacted once it's resolved, when
+		// the entry is written.
+		if isLogValuer(val) {
+			if redacted == nil {
+				redacted = slices.Clone(args)
+			}
+
+			rv := redactedValuer{r: r, key: key, val: val}
+			if f, ok := args[i].(Field); ok && next == i+1 {
+				redacted[i] = Any(f.Key, rv)
+			} else {
+				redacted[next-1] = rv
+			}
+
+			i = next
+			continue
+		}
+
+		if text, ok := r.redactValue(key, val); ok {
+			if redacted == nil {
+				redacted = slices.Clone(args)
+			}
+
+			if f, ok := args[i].(Field); ok && next == i+1 {
+				redacted[i] = String(f.Key, text)
+			} else {
+				redacted[next-1] = text
+			}
+		}
+
+		i = next
+	}
+
+	if redacted == nil {
+		return args
+	}
+
+	return redacted
+}
+
+// redactValue returns the text to write in place of the value of an arg, if
+// any of it must be redacted.
+func (r *redactor) redactValue(key string, val any) (string, bool) {
+	if f, ok := val.(Field); ok {
+		val = f.Value()
+	}
+
+	if s, ok := val.(Redacted); ok {
+		return r.replacement(fmt.Sprint(s.value)), true
+	}
+
+	if r.matchKey(key) {
+		return r.replacement(fmt.Sprint(val)), true
+	}
+
+	if len(r.matchers) == 0 {
+		return "", false
+	}
+
+	var text string
+	switch st := val.(type) {
+	case string:
+		text = st
+	case Quote:
+		text = string(st)
+	case Format:
+		text = fmt.Sprintf(st[0].(string), st[1:]...)
+	case error, fmt.Stringer:
+		text = fmt.Sprint(st)
+	default:
+		return "", false
+	}
+
+	return r.redactMatches(text)
+}
+
+// redactedValuer redacts the value of a LogValuer once it's resolved.
+type redactedValuer struct {
+	r   *redactor
+	key string
+	val any
+}
+
+func (v redactedValuer) LogValue() any {
+	val := resolveValue(v.val)
+
+	if group, ok := asGroup(val); ok && !v.r.matchKey(v.key) {
+		return Group(v.key, v.r.redact(group)...)
+	}
+
+	if text, ok := v.r.redactValue(v.key, val); ok {
+		return text
+	}
+
+	return val
+}
+
+// matchKey reports whether key matches one of the key patterns.
+func (r *redactor) matchKey(key string) bool {
+	for _, k := range r.keys {
+		if containsFold(key, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactMatches returns text with the parts found by the matchers replaced.
+func (r *redactor) redactMatches(text string) (string, bool) {
+	var spans [][]int
+	for _, m := range r.matchers {
+		spans = append(spans, m.FindAllStringIndex(text, -1)...)
+	}
+
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	slices.SortFunc(spans, func(a, b []int) int {
+		return a[0] - b[0]
+	})
+
+	var (
+		sb  strings.Builder
+		pos int
+	)
+
+	for i := 0; i < len(spans); {
+		start, end := spans[i][0], spans[i][1]
+
+		// Merge overlapping matches.
+		for i++; i < len(spans) && spans[i][0] < end; i++ {
+			end = max(end, spans[i][1])
+		}
+
+		sb.WriteString(text[pos:start])
+		sb.WriteString(r.replacement(text[start:end]))
+		pos = end
+	}
+
+	sb.WriteString(text[pos:])
+
+	return sb.String(), true
+}
+
+// replacement returns the text written in place of the sensitive text s.
+func (r *redactor) replacement(s string) string {
+	if len(r.pseudonymKey) == 0 {
+		return RedactedText
+	}
+
+	mac := hmac.New(sha256.New, r.pseudonymKey)
+	_, _ = io.WriteString(mac, s)
+
+	return "[REDACTED:" + hex.EncodeToString(mac.Sum(nil)[:8]) + "]"
+}
+
+// containsFold reports whether substr is within s, ignoring ASCII case.
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+
+	return false
+}
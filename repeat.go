@@ -0,0 +1,169 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RepeatedKey is the key used for the repeat count on an entry written in
+// place of consecutive identical entries, see LoggerOptions.CollapseRepeats.
+const RepeatedKey = "repeated"
+
+// repeatEntry is an entry as seen by the repeatCollapser.
+type repeatEntry struct {
+	logger *intLogger
+	t      time.Time
+	pc     uintptr
+	name   string
+	level  Level
+	msg    string
+
+	// all is the implied args followed by args, and is what entries are
+	// compared on.
+	all     []any
+	implied []any
+	args    []any
+}
+
+func (e *repeatEntry) matches(o *repeatEntry) bool {
+	return e.level == o.level &&
+		e.name == o.name &&
+		e.msg == o.msg &&
+		reflect.DeepEqual(e.all, o.all)
+}
+
+// repeatCollapser holds back consecutive identical entries, writing a single
+// entry with a repeat count in their place. It is shared by a logger and all
+// of its subloggers.
+type repeatCollapser struct {
+	timeout time.Duration
+
+	// mutex is the Locker shared by the loggers, which must be held when
+	// writing. It is always acquired before mu.
+	mutex Locker
+
+	mu    sync.Mutex
+	last  *repeatEntry
+	held  *repeatEntry
+	count int
+	timer *time.Timer
+
+	// gen is incremented each time the timer is stopped, so that a timer
+	// which already fired can tell it is stale.
+	gen uint64
+}
+
+func newRepeatCollapser(timeout time.Duration, mutex Locker) *repeatCollapser {
+	return &repeatCollapser{
+		timeout: timeout,
+		mutex:   mutex,
+	}
+}
+
+// hold reports whether the entry repeats the previous one, in which case it
+// is held back rather than written. Otherwise any held entry is written so
+// that it precedes the new one. The logger mutex must be held by the caller.
+func (c *repeatCollapser) hold(l *intLogger, t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) bool {
+	e := &repeatEntry{
+		logger: l,
+		t:      t,
+		pc:     pc,
+		name:   name,
+		level:  level,
+		msg:    msg,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Copy the args since the entry might outlive the call.
+	e.all = make([]any, 0, len(implied)+len(args))
+	e.all = append(e.all, implied...)
+	e.all = append(e.all, args...)
+	e.implied = e.all[:len(implied):len(implied)]
+	e.args = e.all[len(implied):]
+
+	if c.last != nil && c.last.matches(e) {
+		c.held = e
+		c.count++
+
+		if c.timer == nil {
+			gen := c.gen
+			c.timer = time.AfterFunc(c.timeout, func() {
+				c.expire(gen)
+			})
+		}
+
+		return true
+	}
+
+	c.flushLocked()
+	c.last = e
+
+	return false
+}
+
+// expire writes the held entry once the timeout has passed, unless it was
+// already written and the timer for gen is stale.
+func (c *repeatCollapser) expire(gen uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+
+	c.flushLocked()
+}
+
+// flush writes the held entry, if any, with the repeat count added, and
+// forgets the last entry so that the next one is written whatever it is.
+// The logger mutex must be held by the caller.
+func (c *repeatCollapser) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flushLocked()
+	c.last = nil
+}
+
+// flushLocked writes the held entry, if any, with the repeat count added. Both
+// the logger mutex and mu must be held.
+func (c *repeatCollapser) flushLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+		c.gen++
+	}
+
+	if c.held == nil {
+		return
+	}
+
+	e, count := c.held, c.count
+	c.held, c.count = nil, 0
+
+	args, cs := splitStacktrace(e.args)
+	args = args[:len(args):len(args)]
+
+	// Keep a trailing stacktrace at the end, where it is expected, and give
+	// a trailing value without a key its MissingKey.
+	if trailingValue(args) {
+		last := args[len(args)-1]
+		args = append(args[:len(args)-1:len(args)-1], MissingKey, last)
+	}
+
+	args = append(args, RepeatedKey, count)
+	if cs != "" {
+		args = append(args, cs)
+	}
+
+	e.logger.write(e.t, e.pc, e.name, e.level, e.msg, e.implied, args)
+}
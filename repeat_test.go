@@ -0,0 +1,170 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseRepeats(t *testing.T) {
+	t.Run("collapses consecutive identical entries", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:            "app",
+			Output:          &buf,
+			DisableTime:     true,
+			CollapseRepeats: time.Hour,
+		})
+
+		health := logger.Named("health").With("check", "db")
+		for range 4 {
+			health.Warn("check failed", "status", 503)
+		}
+		health.Info("check passed", "status", 200)
+		health.Info("check passed", "status", 200)
+		health.Info("check passed", "status", 204)
+
+		assert.Equal(t, strings.Join([]string{
+			"[WARN]  app.health: check failed: check=db status=503",
+			"[WARN]  app.health: check failed: check=db status=503 repeated=3",
+			"[INFO]  app.health: check passed: check=db status=200",
+			"[INFO]  app.health: check passed: check=db status=200 repeated=1",
+			"[INFO]  app.health: check passed: check=db status=204",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("compares implied args", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:          &buf,
+			DisableTime:     true,
+			CollapseRepeats: time.Hour,
+		})
+
+		logger.With("peer", "a").Info("connected")
+		logger.With("peer", "b").Info("connected")
+		logger.With("peer", "b").Info("connected")
+		logger.Named("x").With("peer", "b").Info("connected")
+
+		assert.Equal(t, strings.Join([]string{
+			"[INFO]  connected: peer=a",
+			"[INFO]  connected: peer=b",
+			"[INFO]  connected: peer=b repeated=1",
+			"[INFO]  x: connected: peer=b",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("keeps stacktraces and missing keys in place", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:          &buf,
+			DisableTime:     true,
+			CollapseRepeats: time.Hour,
+		})
+
+		for range 2 {
+			logger.Error("failed", "a", 1, CapturedStacktrace("stack"))
+		}
+		for range 2 {
+			logger.Error("extra", "a", 1, "b")
+		}
+		logger.Info("done")
+
+		assert.Equal(t, strings.Join([]string{
+			"[ERROR] failed: a=1",
+			"stack",
+			"[ERROR] failed: a=1 repeated=1",
+			"stack",
+			"[ERROR] extra: a=1 EXTRA_VALUE_AT_END=b",
+			"[ERROR] extra: a=1 EXTRA_VALUE_AT_END=b repeated=1",
+			"[INFO]  done",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("collapses JSON entries", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:          &buf,
+			JSONFormat:      true,
+			CollapseRepeats: time.Hour,
+		})
+
+		for range 3 {
+			logger.Info("tick", "n", 1)
+		}
+		logger.Info("tock")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &raw))
+		assert.Equal(t, "tick", raw["@message"])
+		assert.Equal(t, float64(2), raw[RepeatedKey])
+	})
+
+	t.Run("writes held entries when the timer fires", func(t *testing.T) {
+		var buf lockedBuffer
+
+		logger := New(&LoggerOptions{
+			Output:          &buf,
+			DisableTime:     true,
+			CollapseRepeats: 10 * time.Millisecond,
+		})
+
+		logger.Info("flapping")
+		logger.Info("flapping")
+		logger.Info("flapping")
+
+		assert.Eventually(t, func() bool {
+			return buf.String() == "[INFO]  flapping\n[INFO]  flapping: repeated=2\n"
+		}, 2*time.Second, 5*time.Millisecond)
+
+		// Repeats after the timer fired are still collapsed.
+		logger.Info("flapping")
+		logger.Info("other")
+
+		assert.Equal(t, "[INFO]  flapping\n[INFO]  flapping: repeated=2\n[INFO]  flapping: repeated=1\n[INFO]  other\n", buf.String())
+	})
+
+	t.Run("writes held entries on flush and when the output is reset", func(t *testing.T) {
+		var (
+			first  bufferingBuffer
+			second bytes.Buffer
+		)
+
+		logger := New(&LoggerOptions{
+			Output:          &first,
+			DisableTime:     true,
+			CollapseRepeats: time.Hour,
+		})
+
+		logger.Info("flapping")
+		logger.Info("flapping")
+		require.NoError(t, logger.(Flushable).Flush())
+
+		logger.Info("flapping")
+		logger.Info("flapping")
+		logger.Info("flapping")
+		require.NoError(t, logger.(OutputResettable).ResetOutputWithFlush(&LoggerOptions{Output: &second}, &first))
+		logger.Info("flapping")
+
+		assert.Equal(t, "[INFO]  flapping\n[INFO]  flapping: repeated=1\n[INFO]  flapping\n[INFO]  flapping: repeated=2\n", first.String())
+		assert.Equal(t, "[INFO]  flapping\n", second.String())
+	})
+}
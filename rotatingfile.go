@@ -0,0 +1,488 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingFileTimeFormat is the format of the timestamp added to the names of
+// rotated files. It sorts lexically and avoids characters that are awkward in
+// file names.
+const rotatingFileTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFileOptions can be used to configure a new RotatingFile.
+type RotatingFileOptions struct {
+	// Path of the file to write to. Its directory is created if needed.
+	Path string
+
+	// MaxSize is the size in bytes after which the file is rotated. Zero
+	// disables size based rotation.
+	MaxSize int64
+
+	// Interval is how long to write to a file before rotating it. Zero
+	// disables time based rotation.
+	Interval time.Duration
+
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of
+	// them, subject to MaxAge.
+	MaxBackups int
+
+	// MaxAge is how long to keep rotated files, based on the time they were
+	// rotated. Zero keeps them regardless of age, subject to MaxBackups.
+	MaxAge time.Duration
+
+	// Compress causes rotated files to be gzipped in the background.
+	Compress bool
+
+	// FileMode is used when creating files. Defaults to 0644.
+	FileMode os.FileMode
+
+	// A function which is called to get the current time, used when
+	// deciding to rotate and naming rotated files. Defaults to time.Now.
+	TimeFn TimeFunction
+}
+
+// Make sure that RotatingFile is Flushable
+var _ Flushable = &RotatingFile{}
+
+// RotatingFile is an io.Writer which writes to a file, rotating it once it
+// reaches a size or age limit. Rotated files are renamed to include the time
+// of rotation, e.g. app.log becomes app-2006-01-02T15-04-05.000.log, and are
+// optionally compressed and removed once past the retention limits.
+//
+// Rotation only happens between calls to Write. Each log entry, including all
+// the lines of multi-line values and any stacktrace, is passed to a single
+// Write, so entries are never split across files. An entry larger than
+// MaxSize is written whole to a new file. If the file can't be rotated,
+// entries are still appended to it, the rotation is tried again with the
+// next Write, and the error is returned by Flush.
+//
+// RotatingFile is Flushable, and so can be used with ResetOutputWithFlush.
+type RotatingFile struct {
+	opts RotatingFileOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	// err holds the first error from background work or a failed rotation,
+	// returned by Flush.
+	errMu sync.Mutex
+	err   error
+
+	mill chan struct{}
+	done chan struct{}
+}
+
+// NewRotatingFile opens the file given in opts for appending, creating it if
+// needed, and starts the goroutine used to compress and remove rotated files.
+// Close must be called to stop it.
+func NewRotatingFile(opts *RotatingFileOptions) (*RotatingFile, error) {
+	if opts == nil || opts.Path == "" {
+		return nil, errors.New("rotating file path is empty")
+	}
+
+	r := &RotatingFile{
+		opts: *opts,
+		mill: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	if r.opts.FileMode == 0 {
+		r.opts.FileMode = 0o644
+	}
+	if r.opts.TimeFn == nil {
+		r.opts.TimeFn = time.Now
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	go r.runMill()
+
+	// Tidy up anything left over from a previous run.
+	r.signalMill()
+
+	return r, nil
+}
+
+// open opens the file for appending. The mutex must be held.
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.opts.Path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(r.opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, r.opts.FileMode)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = info.Size()
+	r.openedAt = r.opts.TimeFn()
+
+	return nil
+}
+
+// Write writes p to the file, rotating it first if p would take it past
+// MaxSize or it has been open for longer than Interval.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
+	// A failed rotation may have left no file open, so try again.
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			if r.file == nil {
+				return 0, err
+			}
+
+			// The file couldn't be renamed but is open again, so keep
+			// writing to it, rotating with the next Write, and report the
+			// error from Flush.
+			r.setErr(err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+func (r *RotatingFile) shouldRotate(n int64) bool {
+	// Never rotate an empty file, there would be nothing to keep.
+	if r.size == 0 {
+		return false
+	}
+
+	if r.opts.MaxSize > 0 && r.size+n > r.opts.MaxSize {
+		return true
+	}
+
+	if r.opts.Interval > 0 && r.opts.TimeFn().Sub(r.openedAt) >= r.opts.Interval {
+		return true
+	}
+
+	return false
+}
+
+// Rotate rotates the file immediately, such as in response to a signal.
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+
+	return r.rotate()
+}
+
+// rotate renames the current file aside and opens a new one. If the file
+// can't be renamed, it is opened again to append to. If it can't be opened,
+// the file is left nil for Write to try again. The mutex must be held.
+func (r *RotatingFile) rotate() error {
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+
+	if err == nil {
+		backup := r.backupName(r.opts.TimeFn())
+		if err = os.Rename(r.opts.Path, backup); errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+
+	if openErr := r.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	r.signalMill()
+
+	return nil
+}
+
+// backupName returns an unused name for a file rotated at t.
+func (r *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := r.nameParts()
+	base := filepath.Join(dir, prefix+t.UTC().Format(rotatingFileTimeFormat))
+
+	name := base + ext
+	for i := 1; ; i++ {
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+		name = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+}
+
+// nameParts splits the path into the directory, the prefix shared by all
+// rotated files and the extension.
+func (r *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(r.opts.Path)
+	base := filepath.Base(r.opts.Path)
+	ext = filepath.Ext(base)
+	prefix = strings.TrimSuffix(base, ext) + "-"
+	return dir, prefix, ext
+}
+
+func fileExists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// Flush commits the file to stable storage, and returns the first error
+// encountered rotating, compressing or removing files since the last Flush.
+func (r *RotatingFile) Flush() error {
+	r.errMu.Lock()
+	err := r.err
+	r.err = nil
+	r.errMu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed || r.file == nil {
+		return nil
+	}
+
+	return r.file.Sync()
+}
+
+// Close closes the file and waits for any background compression and removal
+// of rotated files to complete.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+	}
+	r.mu.Unlock()
+
+	close(r.mill)
+	<-r.done
+
+	if err != nil {
+		return err
+	}
+
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+
+	return r.err
+}
+
+func (r *RotatingFile) signalMill() {
+	select {
+	case r.mill <- struct{}{}:
+	default:
+	}
+}
+
+// runMill compresses and removes rotated files each time it is signalled,
+// until Close is called.
+func (r *RotatingFile) runMill() {
+	defer close(r.done)
+
+	for range r.mill {
+		if err := r.millOnce(); err != nil {
+			r.setErr(err)
+		}
+	}
+}
+
+// setErr records err to be returned by Flush, unless there's already an
+// error recorded.
+func (r *RotatingFile) setErr(err error) {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// rotatedFile is a file previously rotated by a RotatingFile.
+type rotatedFile struct {
+	path       string
+	rotatedAt  time.Time
+	compressed bool
+}
+
+// millOnce applies the retention limits to the rotated files and compresses
+// those that remain.
+func (r *RotatingFile) millOnce() error {
+	files, err := r.rotatedFiles()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	now := r.opts.TimeFn()
+	keep := files[:0]
+	for i, f := range files {
+		expired := r.opts.MaxAge > 0 && now.Sub(f.rotatedAt) > r.opts.MaxAge
+		excess := r.opts.MaxBackups > 0 && i >= r.opts.MaxBackups
+
+		if expired || excess {
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		keep = append(keep, f)
+	}
+
+	if r.opts.Compress {
+		for _, f := range keep {
+			if f.compressed {
+				continue
+			}
+
+			if err := compressFile(f.path, r.opts.FileMode); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// rotatedFiles returns the rotated files, most recent first.
+func (r *RotatingFile) rotatedFiles() ([]rotatedFile, error) {
+	dir, prefix, ext := r.nameParts()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []rotatedFile
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		name := e.Name()
+		f := rotatedFile{path: filepath.Join(dir, name)}
+
+		if trimmed, ok := strings.CutSuffix(name, ".gz"); ok {
+			name = trimmed
+			f.compressed = true
+		}
+
+		// Ignore the temporary file of an interrupted compression.
+		if strings.HasSuffix(name, ".gz.tmp") {
+			continue
+		}
+
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if len(stamp) < len(rotatingFileTimeFormat) {
+			continue
+		}
+
+		t, err := time.Parse(rotatingFileTimeFormat, stamp[:len(rotatingFileTimeFormat)])
+		if err != nil {
+			continue
+		}
+
+		f.rotatedAt = t
+		files = append(files, f)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if !files[i].rotatedAt.Equal(files[j].rotatedAt) {
+			return files[i].rotatedAt.After(files[j].rotatedAt)
+		}
+		return files[i].path > files[j].path
+	})
+
+	return files, nil
+}
+
+// compressFile gzips the file at path to path.gz, removing the original once
+// the compressed copy is complete.
+func compressFile(path string, mode os.FileMode) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,333 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a TimeFunction which only moves when advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestRotatingFile(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("rotates by size between entries", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := &fakeClock{now: start}
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{
+			Path:    filepath.Join(dir, "app.log"),
+			MaxSize: 40,
+			TimeFn:  clock.Now,
+		})
+		require.NoError(t, err)
+
+		logger := New(&LoggerOptions{
+			Output:      rf,
+			DisableTime: true,
+		})
+
+		logger.Info("first", "n", 1)
+		clock.Advance(time.Second)
+		logger.Info("second", "text", "line one\nline two")
+		clock.Advance(time.Second)
+		logger.Info("third", "n", 3)
+
+		require.NoError(t, rf.Close())
+
+		assert.Equal(t, []string{
+			"app-2026-01-02T03-04-06.000.log",
+			"app-2026-01-02T03-04-07.000.log",
+			"app.log",
+		}, listDir(t, dir))
+
+		assert.Equal(t, "[INFO]  first: n=1\n", readFile(t, filepath.Join(dir, "app-2026-01-02T03-04-06.000.log")))
+
+		// The multi-line entry is larger than MaxSize, but is kept whole.
+		assert.Equal(t, "[INFO]  second:\n  text=\n  | line one\n  | line two\n  \n",
+			readFile(t, filepath.Join(dir, "app-2026-01-02T03-04-07.000.log")))
+
+		assert.Equal(t, "[INFO]  third: n=3\n", readFile(t, filepath.Join(dir, "app.log")))
+	})
+
+	t.Run("rotates by interval", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := &fakeClock{now: start}
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{
+			Path:     filepath.Join(dir, "app.log"),
+			Interval: time.Hour,
+			TimeFn:   clock.Now,
+		})
+		require.NoError(t, err)
+
+		_, err = rf.Write([]byte("a\n"))
+		require.NoError(t, err)
+
+		clock.Advance(59 * time.Minute)
+		_, err = rf.Write([]byte("b\n"))
+		require.NoError(t, err)
+
+		clock.Advance(time.Minute)
+		_, err = rf.Write([]byte("c\n"))
+		require.NoError(t, err)
+
+		require.NoError(t, rf.Close())
+
+		assert.Equal(t, "a\nb\n", readFile(t, filepath.Join(dir, "app-2026-01-02T04-04-05.000.log")))
+		assert.Equal(t, "c\n", readFile(t, filepath.Join(dir, "app.log")))
+	})
+
+	t.Run("keeps a limited number of backups", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := &fakeClock{now: start}
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{
+			Path:       filepath.Join(dir, "app.log"),
+			MaxBackups: 2,
+			TimeFn:     clock.Now,
+		})
+		require.NoError(t, err)
+
+		for _, s := range []string{"a", "b", "c", "d"} {
+			_, err = rf.Write([]byte(s))
+			require.NoError(t, err)
+			clock.Advance(time.Second)
+			require.NoError(t, rf.Rotate())
+		}
+
+		require.NoError(t, rf.Close())
+
+		assert.Equal(t, []string{
+			"app-2026-01-02T03-04-08.000.log",
+			"app-2026-01-02T03-04-09.000.log",
+			"app.log",
+		}, listDir(t, dir))
+		assert.Equal(t, "d", readFile(t, filepath.Join(dir, "app-2026-01-02T03-04-09.000.log")))
+	})
+
+	t.Run("removes backups past the max age", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := &fakeClock{now: start}
+
+		// Left over from an earlier run, and removed at startup.
+		old := filepath.Join(dir, "app-2025-12-01T00-00-00.000.log.gz")
+		require.NoError(t, os.WriteFile(old, nil, 0o644))
+
+		unrelated := filepath.Join(dir, "app-notes.log")
+		require.NoError(t, os.WriteFile(unrelated, nil, 0o644))
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{
+			Path:   filepath.Join(dir, "app.log"),
+			MaxAge: 24 * time.Hour,
+			TimeFn: clock.Now,
+		})
+		require.NoError(t, err)
+
+		_, err = rf.Write([]byte("a"))
+		require.NoError(t, err)
+		require.NoError(t, rf.Rotate())
+
+		require.NoError(t, rf.Close())
+
+		assert.Equal(t, []string{
+			"app-2026-01-02T03-04-05.000.log",
+			"app-notes.log",
+			"app.log",
+		}, listDir(t, dir))
+	})
+
+	t.Run("compresses rotated files", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := &fakeClock{now: start}
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{
+			Path:     filepath.Join(dir, "app.log"),
+			Compress: true,
+			TimeFn:   clock.Now,
+		})
+		require.NoError(t, err)
+
+		_, err = rf.Write([]byte("first\n"))
+		require.NoError(t, err)
+		require.NoError(t, rf.Rotate())
+
+		// Rotating twice within the same millisecond doesn't clobber the
+		// earlier file.
+		_, err = rf.Write([]byte("second\n"))
+		require.NoError(t, err)
+		require.NoError(t, rf.Rotate())
+
+		require.NoError(t, rf.Close())
+
+		assert.Equal(t, []string{
+			"app-2026-01-02T03-04-05.000.1.log.gz",
+			"app-2026-01-02T03-04-05.000.log.gz",
+			"app.log",
+		}, listDir(t, dir))
+
+		for name, want := range map[string]string{
+			"app-2026-01-02T03-04-05.000.log.gz":   "first\n",
+			"app-2026-01-02T03-04-05.000.1.log.gz": "second\n",
+		} {
+			f, err := os.Open(filepath.Join(dir, name))
+			require.NoError(t, err)
+
+			gz, err := gzip.NewReader(f)
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			assert.Equal(t, want, string(data))
+
+			require.NoError(t, f.Close())
+		}
+	})
+
+	t.Run("appends to an existing file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "logs", "app.log")
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{Path: path, MaxSize: 10})
+		require.NoError(t, err)
+		_, err = rf.Write([]byte("12345\n"))
+		require.NoError(t, err)
+		require.NoError(t, rf.Close())
+
+		rf, err = NewRotatingFile(&RotatingFileOptions{Path: path, MaxSize: 10})
+		require.NoError(t, err)
+		_, err = rf.Write([]byte("678\n"))
+		require.NoError(t, err)
+		_, err = rf.Write([]byte("9\n"))
+		require.NoError(t, err)
+		require.NoError(t, rf.Close())
+
+		assert.Equal(t, "9\n", readFile(t, path))
+		assert.Len(t, listDir(t, filepath.Dir(path)), 2)
+
+		_, err = rf.Write([]byte("closed"))
+		assert.ErrorIs(t, err, os.ErrClosed)
+	})
+
+	t.Run("is flushed by ResetOutputWithFlush", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{Path: path})
+		require.NoError(t, err)
+		defer func() { _ = rf.Close() }()
+
+		logger := New(&LoggerOptions{
+			Output:      rf,
+			DisableTime: true,
+		})
+		logger.Info("hello")
+
+		var second bytes.Buffer
+		require.NoError(t, logger.(OutputResettable).ResetOutputWithFlush(&LoggerOptions{
+			Output: &second,
+		}, rf))
+
+		assert.True(t, strings.HasSuffix(readFile(t, path), "[INFO]  hello\n"))
+	})
+
+	t.Run("keeps writing if the file can't be renamed", func(t *testing.T) {
+		dir := t.TempDir()
+
+		// The name leaves no room for the time added to rotated files.
+		path := filepath.Join(dir, strings.Repeat("a", 240)+".log")
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{Path: path, MaxSize: 10})
+		require.NoError(t, err)
+		defer func() { _ = rf.Close() }()
+
+		_, err = rf.Write([]byte("12345\n"))
+		require.NoError(t, err)
+		_, err = rf.Write([]byte("6789\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "12345\n6789\n", readFile(t, path))
+		assert.Error(t, rf.Flush())
+		assert.NoError(t, rf.Flush())
+	})
+
+	t.Run("reopens the file after a failed rotation", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "logs")
+		path := filepath.Join(dir, "app.log")
+
+		rf, err := NewRotatingFile(&RotatingFileOptions{Path: path, MaxSize: 10})
+		require.NoError(t, err)
+		defer func() { _ = rf.Close() }()
+
+		_, err = rf.Write([]byte("12345\n"))
+		require.NoError(t, err)
+
+		// With a file in place of the directory, the file can be neither
+		// renamed nor opened again.
+		require.NoError(t, os.RemoveAll(dir))
+		require.NoError(t, os.WriteFile(dir, nil, 0o600))
+
+		_, err = rf.Write([]byte("6789\n"))
+		assert.Error(t, err)
+		assert.NoError(t, rf.Flush())
+
+		require.NoError(t, os.Remove(dir))
+
+		_, err = rf.Write([]byte("after\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "after\n", readFile(t, path))
+	})
+}
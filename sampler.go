@@ -0,0 +1,163 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SamplingOptions configures sampling of repeated log entries, which caps the
+// output produced by a hot path logging the same message over and over.
+// Entries are grouped by level and message. Within each Tick, the first
+// Initial entries of a group are logged and then only every Thereafter-th
+// entry after that. Once the Tick is over, a summary entry is logged for
+// every group that had entries dropped, giving the number dropped. The
+// summary has the level of the entries it counts, and isn't checked against
+// the level of the logger again, as those entries already were.
+type SamplingOptions struct {
+	// Tick is the length of each sampling window. Defaults to one second.
+	Tick time.Duration
+
+	// Initial is the number of entries of each group logged per Tick before
+	// sampling begins. Defaults to one.
+	Initial int
+
+	// Thereafter causes every Thereafter-th entry past Initial to be logged.
+	// If zero, all entries past Initial are dropped until the next Tick.
+	Thereafter int
+}
+
+// SampledMessage is the message of the summary entry logged at the end of a
+// sampling window, see SamplingOptions.
+const SampledMessage = "log entries dropped by sampling"
+
+type samplerKey struct {
+	level Level
+	msg   string
+}
+
+type samplerCount struct {
+	seen    int
+	dropped int
+
+	// name of the logger that most recently had an entry dropped, used for
+	// the summary entry.
+	name string
+}
+
+// sampler tracks the entries seen during the current window. It is shared by
+// a logger and all of its subloggers.
+type sampler struct {
+	tick       time.Duration
+	initial    int
+	thereafter int
+
+	// summarize is called with the entries that had some dropped once the
+	// window is over.
+	summarize func(summaries []samplerSummary)
+
+	mu     sync.Mutex
+	counts map[samplerKey]*samplerCount
+	armed  bool
+}
+
+// samplerSummary describes the entries dropped for one group.
+type samplerSummary struct {
+	name    string
+	level   Level
+	msg     string
+	dropped int
+}
+
+func newSampler(opts *SamplingOptions, summarize func([]samplerSummary)) *sampler {
+	tick := opts.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	initial := opts.Initial
+	if initial <= 0 {
+		initial = 1
+	}
+
+	return &sampler{
+		tick:       tick,
+		initial:    initial,
+		thereafter: opts.Thereafter,
+		summarize:  summarize,
+		counts:     make(map[samplerKey]*samplerCount),
+	}
+}
+
+// sample reports whether the entry should be logged.
+func (s *sampler) sample(name string, level Level, msg string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The window begins with the first entry seen, so that an idle logger
+	// doesn't keep a timer running.
+	if !s.armed {
+		s.armed = true
+		time.AfterFunc(s.tick, s.flush)
+	}
+
+	key := samplerKey{level: level, msg: msg}
+
+	c, ok := s.counts[key]
+	if !ok {
+		c = new(samplerCount)
+		s.counts[key] = c
+	}
+
+	c.seen++
+	if c.seen <= s.initial {
+		return true
+	}
+
+	if s.thereafter > 0 && (c.seen-s.initial)%s.thereafter == 0 {
+		return true
+	}
+
+	c.dropped++
+	c.name = name
+
+	return false
+}
+
+// flush ends the current window and passes the summary of dropped entries on.
+func (s *sampler) flush() {
+	s.mu.Lock()
+
+	var summaries []samplerSummary
+	for key, c := range s.counts {
+		if c.dropped > 0 {
+			summaries = append(summaries, samplerSummary{
+				name:    c.name,
+				level:   key.level,
+				msg:     key.msg,
+				dropped: c.dropped,
+			})
+		}
+	}
+
+	s.counts = make(map[samplerKey]*samplerCount)
+	s.armed = false
+
+	s.mu.Unlock()
+
+	if len(summaries) == 0 {
+		return
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].level != summaries[j].level {
+			return summaries[i].level < summaries[j].level
+		}
+		return summaries[i].msg < summaries[j].msg
+	})
+
+	s.summarize(summaries)
+}
@@ -0,0 +1,157 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lockedBuffer is a bytes.Buffer which is safe to read while the logger is
+// writing to it from another goroutine.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSampling(t *testing.T) {
+	t.Run("logs the initial entries and then every nth", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+			Sampling: &SamplingOptions{
+				Tick:       time.Hour,
+				Initial:    2,
+				Thereafter: 3,
+			},
+		})
+
+		for i := range 10 {
+			logger.Info("connection closed", "i", i)
+		}
+		logger.Warn("connection closed", "i", 10)
+		logger.Info("other")
+
+		assert.Equal(t, strings.Join([]string{
+			"[INFO]  connection closed: i=0",
+			"[INFO]  connection closed: i=1",
+			"[INFO]  connection closed: i=4",
+			"[INFO]  connection closed: i=7",
+			"[WARN]  connection closed: i=10",
+			"[INFO]  other",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("summarizes dropped entries at the end of the window", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:        "app",
+			Output:      &buf,
+			DisableTime: true,
+			Sampling: &SamplingOptions{
+				Tick:    time.Hour,
+				Initial: 1,
+			},
+		})
+
+		sub := logger.Named("conn").With("peer", "a")
+		for range 5 {
+			sub.Debug("retrying")
+			sub.Error("retrying")
+		}
+		logger.Error("single")
+
+		buf.Reset()
+		logger.(*intLogger).sampler.flush()
+
+		assert.Equal(t, strings.Join([]string{
+			"[ERROR] app.conn: " + SampledMessage + ": message=retrying dropped=4",
+			"",
+		}, "\n"), buf.String())
+
+		// The next window starts counting from scratch.
+		buf.Reset()
+		sub.Error("retrying")
+		sub.Error("retrying")
+		assert.Equal(t, "[ERROR] app.conn: retrying: peer=a\n", buf.String())
+	})
+
+	t.Run("logs the first entry of each group by default", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+			Sampling:    &SamplingOptions{},
+		})
+
+		for range 3 {
+			logger.Info("hello")
+			logger.Warn("hello")
+		}
+
+		assert.Equal(t, "[INFO]  hello\n[WARN]  hello\n", buf.String())
+	})
+
+	t.Run("shares the sampler with subloggers", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+			Sampling: &SamplingOptions{
+				Tick:    time.Hour,
+				Initial: 1,
+			},
+		})
+
+		logger.Info("hello")
+		logger.Named("a").Info("hello")
+		logger.With("b", 1).Info("hello")
+
+		assert.Equal(t, "[INFO]  hello\n", buf.String())
+	})
+
+	t.Run("flushes on each tick", func(t *testing.T) {
+		var buf lockedBuffer
+
+		logger := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+			Sampling: &SamplingOptions{
+				Tick:    10 * time.Millisecond,
+				Initial: 1,
+			},
+		})
+
+		logger.Info("noisy")
+		logger.Info("noisy")
+		logger.Info("noisy")
+
+		assert.Eventually(t, func() bool {
+			return buf.String() == "[INFO]  noisy\n[INFO]  "+SampledMessage+": message=noisy dropped=2\n"
+		}, 2*time.Second, 5*time.Millisecond)
+	})
+}
@@ -0,0 +1,569 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// SlogModuleKey is the attribute key used to carry a logger's name across the
+// slog bridge. A Logger created by FromSlogHandler adds its name under this key,
+// and a handler created by NewSlogHandler treats a top level string attribute
+// with this key as the name of a sub-logger, as if created with Named.
+const SlogModuleKey = "@module"
+
+// Make sure that slogHandler is a slog.Handler
+var _ slog.Handler = &slogHandler{}
+
+// slogHandler is a slog.Handler which sends records to a Logger.
+type slogHandler struct {
+	logger Logger
+
+	// prefix is prepended to all attribute keys, and is built up from the
+	// names passed to WithGroup separated by dots.
+	prefix string
+}
+
+// NewSlogHandler returns a slog.Handler which writes to the given Logger. This
+// allows packages that log via log/slog to send their output through a Logger.
+//
+// Levels are mapped onto the closest Level, with anything below
+// slog.LevelDebug treated as Trace. Groups are flattened into dotted key
+// names, so slog.Group("req", "id", 1) is logged as "req.id"=1. LogValuer
+// values are resolved before being passed to the Logger.
+func NewSlogHandler(logger Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+// Enabled reports whether the Logger would emit entries at the given level.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	switch levelFromSlog(level) {
+	case Trace:
+		return h.logger.IsTrace()
+	case Debug:
+		return h.logger.IsDebug()
+	case Info:
+		return h.logger.IsInfo()
+	case Warn:
+		return h.logger.IsWarn()
+	default:
+		return h.logger.IsError()
+	}
+}
+
+// Handle sends the record to the Logger.
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	var module string
+
+	args := make([]any, 0, 2*r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		if isSlogModule(h.prefix, a) {
+			module = a.Value.String()
+			return true
+		}
+
+		args = appendSlogAttr(args, h.prefix, a)
+		return true
+	})
+
+	level := levelFromSlog(r.Level)
+
+	// Our own logger can use the time and location from the record directly,
+	// everything else goes through the Logger interface.
+	if il, ok := h.logger.(*intLogger); ok {
+		if level < il.GetLevel() {
+			return nil
+		}
+
+		name := il.Name()
+		if module != "" {
+			if name != "" {
+				name = name + "." + module
+			} else {
+				name = module
+			}
+		}
+
+		var pc uintptr
+		if il.callerOffset > 0 {
+			pc = r.PC
+		}
+
+		il.emit(r.Time, pc, name, level, r.Message, args...)
+		return nil
+	}
+
+	logger := h.logger
+	if module != "" {
+		logger = logger.Named(module)
+	}
+
+	logger.Log(level, r.Message, args...)
+	return nil
+}
+
+// WithAttrs returns a handler whose Logger always includes the given
+// attributes.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	logger := h.logger
+
+	args := make([]any, 0, 2*len(attrs))
+	for _, a := range attrs {
+		if isSlogModule(h.prefix, a) {
+			logger = logger.Named(a.Value.String())
+			continue
+		}
+
+		args = appendSlogAttr(args, h.prefix, a)
+	}
+
+	if len(args) > 0 {
+		logger = logger.With(args...)
+	}
+
+	return &slogHandler{logger: logger, prefix: h.prefix}
+}
+
+// WithGroup returns a handler which qualifies all subsequent attribute keys
+// with the given group name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &slogHandler{logger: h.logger, prefix: h.prefix + name + "."}
+}
+
+// isSlogModule reports whether the attribute carries a logger name, which is
+// only recognized outside of any group.
+func isSlogModule(prefix string, a slog.Attr) bool {
+	return prefix == "" && a.Key == SlogModuleKey && a.Value.Kind() == slog.KindString
+}
+
+// appendSlogAttr flattens the attribute into key/value pairs, appending them
+// to args. Empty attributes and empty groups are dropped, and groups with an
+// empty key are inlined, as required by the slog.Handler contract.
+func appendSlogAttr(args []any, prefix string, a slog.Attr) []any {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return args
+		}
+
+		if a.Key != "" {
+			prefix = prefix + a.Key + "."
+		}
+
+		for _, ga := range attrs {
+			args = appendSlogAttr(args, prefix, ga)
+		}
+
+		return args
+	}
+
+	if a.Equal(slog.Attr{}) {
+		return args
+	}
+
+	return append(args, prefix+a.Key, a.Value.Any())
+}
+
+// levelFromSlog maps a slog.Level onto the closest Level.
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return Trace
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warn
+	default:
+		return Error
+	}
+}
+
+// levelToSlog maps a Level onto a slog.Level. Trace is one step below
+// slog.LevelDebug, mirroring the spacing used by slog's own levels.
+func levelToSlog(level Level) slog.Level {
+	switch level {
+	case Trace:
+		return slog.LevelDebug - 4
+	case Debug:
+		return slog.LevelDebug
+	case Warn:
+		return slog.LevelWarn
+	case Error:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Make sure that slogLogger is a Logger
+var _ Logger = &slogLogger{}
+
+// slogLogger is a Logger which writes to a slog.Handler.
+type slogLogger struct {
+	handler      slog.Handler
+	name         string
+	implied      []any
+	groups       []string
+	callerOffset int
+	timeFn       TimeFunction
+	level        *int32
+
+	exclude func(level Level, msg string, args ...any) bool
+
+	// create subloggers with their own level setting
+	independentLevels bool
+
+	// Optional per-name levels, see intLogger
+	levelDirectives *LevelDirectives
+	directiveRoot   string
+	directiveCache  *atomic.Uint64
+
+	subloggerHook func(sub Logger) Logger
+}
+
+// FromSlogHandler returns a Logger which writes to the given slog.Handler.
+// This allows code that expects a Logger to send its output to any slog
+// handler. The Name, Level, IncludeLocation, AdditionalLocationOffset, TimeFn,
+// Exclude, IndependentLevels, LevelDirectives and SubloggerHook options are
+// honored, the remaining options control formatting and are left to the
+// handler.
+//
+// The logger name is added to each record under SlogModuleKey.
+func FromSlogHandler(handler slog.Handler, opts *LoggerOptions) Logger {
+	if opts == nil {
+		opts = &LoggerOptions{}
+	}
+
+	level := opts.Level
+	if level == NoLevel {
+		level = DefaultLevel
+	}
+
+	l := &slogLogger{
+		handler:           handler,
+		name:              opts.Name,
+		timeFn:            time.Now,
+		level:             new(int32),
+		exclude:           opts.Exclude,
+		independentLevels: opts.IndependentLevels,
+		levelDirectives:   opts.LevelDirectives,
+		directiveRoot:     opts.Name,
+		directiveCache:    new(atomic.Uint64),
+		subloggerHook:     opts.SubloggerHook,
+	}
+	if opts.IncludeLocation {
+		l.callerOffset = offsetSlogLogger + opts.AdditionalLocationOffset
+	}
+	if opts.TimeFn != nil {
+		l.timeFn = opts.TimeFn
+	}
+	if l.subloggerHook == nil {
+		l.subloggerHook = identityHook
+	}
+
+	atomic.StoreInt32(l.level, int32(level))
+
+	return l
+}
+
+// offsetSlogLogger is the runtime.Callers skip value which identifies the
+// caller to one of the Warn, Info, Log, etc methods from within log.
+const offsetSlogLogger = 3
+
+func (l *slogLogger) log(level Level, msg string, args ...any) {
+	if level < l.GetLevel() {
+		return
+	}
+
+	ctx := context.Background()
+	slevel := levelToSlog(level)
+	if !l.handler.Enabled(ctx, slevel) {
+		return
+	}
+
+	if l.exclude != nil && l.exclude(level, msg, args...) {
+		return
+	}
+
+	var pc uintptr
+	if l.callerOffset > 0 {
+		var pcs [1]uintptr
+		if runtime.Callers(l.callerOffset, pcs[:]) > 0 {
+			pc = pcs[0]
+		}
+	}
+
+	r := slog.NewRecord(l.timeFn(), slevel, msg, pc)
+	if l.name != "" {
+		r.AddAttrs(slog.String(SlogModuleKey, l.name))
+	}
+	if len(l.groups) > 0 {
+		r.AddAttrs(slogAttrs(joinGroups(l.implied, wrapGroups(l.groups, args)))...)
+	} else {
+		r.AddAttrs(slogAttrs(l.implied)...)
+		r.AddAttrs(slogAttrs(args)...)
+	}
+
+	_ = l.handler.Handle(ctx, r)
+}
+
+// slogAttrs converts key/value pairs into attributes, applying the same
+// handling of fields, Format values, stacktraces and missing keys as the other
+// loggers do. Groups become slog groups.
+func slogAttrs(args []any) []slog.Attr {
+	if len(args) == 0 {
+		return nil
+	}
+
+	args, stacktrace := splitStacktrace(args)
+
+	attrs := make([]slog.Attr, 0, (len(args)+3)/2)
+
+	for i := 0; i < len(args); {
+		var (
+			key string
+			val any
+		)
+
+		key, val, i = nextArg(args, i)
+		val = resolveValue(val)
+
+		if f, ok := val.(Field); ok {
+			if group, ok := f.group(); ok {
+				attrs = append(attrs, slog.Attr{Key: key, Value: slog.GroupValue(slogAttrs(group)...)})
+				continue
+			}
+
+			val = f.Value()
+		}
+
+		switch v := val.(type) {
+		case Format:
+			attrs = append(attrs, slog.String(key, fmt.Sprintf(v[0].(string), v[1:]...)))
+		case Quote:
+			attrs = append(attrs, slog.String(key, string(v)))
+		case CapturedStacktrace:
+			attrs = append(attrs, slog.String(key, string(v)))
+		default:
+			attrs = append(attrs, slog.Any(key, v))
+		}
+	}
+
+	if stacktrace != "" {
+		attrs = append(attrs, slog.String("stacktrace", string(stacktrace)))
+	}
+
+	return attrs
+}
+
+// Emit the message and args at the provided level
+func (l *slogLogger) Log(level Level, msg string, args ...any) {
+	l.log(level, msg, args...)
+}
+
+// LogFields emits the message and fields at the provided level. The fields
+// are only boxed once the entry passes the level check.
+func (l *slogLogger) LogFields(level Level, msg string, fields ...Field) {
+	if level < l.GetLevel() {
+		return
+	}
+
+	l.log(level, msg, fieldArgs(fields)...)
+}
+
+// Emit the message and args at TRACE level
+func (l *slogLogger) Trace(msg string, args ...any) {
+	l.log(Trace, msg, args...)
+}
+
+// Emit the message and args at DEBUG level
+func (l *slogLogger) Debug(msg string, args ...any) {
+	l.log(Debug, msg, args...)
+}
+
+// Emit the message and args at INFO level
+func (l *slogLogger) Info(msg string, args ...any) {
+	l.log(Info, msg, args...)
+}
+
+// Emit the message and args at WARN level
+func (l *slogLogger) Warn(msg string, args ...any) {
+	l.log(Warn, msg, args...)
+}
+
+// Emit the message and args at ERROR level
+func (l *slogLogger) Error(msg string, args ...any) {
+	l.log(Error, msg, args...)
+}
+
+// Indicate that the logger would emit TRACE level logs
+func (l *slogLogger) IsTrace() bool {
+	return l.isLevel(Trace)
+}
+
+// Indicate that the logger would emit DEBUG level logs
+func (l *slogLogger) IsDebug() bool {
+	return l.isLevel(Debug)
+}
+
+// Indicate that the logger would emit INFO level logs
+func (l *slogLogger) IsInfo() bool {
+	return l.isLevel(Info)
+}
+
+// Indicate that the logger would emit WARN level logs
+func (l *slogLogger) IsWarn() bool {
+	return l.isLevel(Warn)
+}
+
+// Indicate that the logger would emit ERROR level logs
+func (l *slogLogger) IsError() bool {
+	return l.isLevel(Error)
+}
+
+func (l *slogLogger) isLevel(level Level) bool {
+	return l.GetLevel() <= level && l.handler.Enabled(context.Background(), levelToSlog(level))
+}
+
+// ImpliedArgs returns the loggers implied args
+func (l *slogLogger) ImpliedArgs() []any {
+	return l.implied
+}
+
+// Return a sub-Logger for which every emitted log message will contain
+// the given key/value pairs. Like intLogger, the implied args are kept
+// sorted by key and later values replace earlier ones.
+func (l *slogLogger) With(args ...any) Logger {
+	args = expandFields(args)
+
+	var extra any
+
+	if trailingValue(args) {
+		extra = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	sl := l.copy()
+
+	sl.implied = mergeArgs(l.implied, wrapGroups(l.groups, args))
+
+	if extra != nil {
+		sl.implied = append(sl.implied, MissingKey, extra)
+	}
+
+	return l.subloggerHook(sl)
+}
+
+// Return a sub-Logger which nests the key/value pairs given to With and to
+// each entry under the named group, as slog attribute groups.
+func (l *slogLogger) WithGroup(name string) Logger {
+	if name == "" {
+		return l
+	}
+
+	sl := l.copy()
+	sl.groups = append(l.groups[:len(l.groups):len(l.groups)], name)
+
+	return l.subloggerHook(sl)
+}
+
+// Name returns the loggers name
+func (l *slogLogger) Name() string {
+	return l.name
+}
+
+// Create a new sub-Logger that a name descending from the current name.
+func (l *slogLogger) Named(name string) Logger {
+	sl := l.copy()
+
+	if sl.name != "" {
+		sl.name = sl.name + "." + name
+	} else {
+		sl.name = name
+	}
+
+	return l.subloggerHook(sl)
+}
+
+// Create a new sub-Logger with an explicit name. This ignores the current
+// name.
+func (l *slogLogger) ResetNamed(name string) Logger {
+	sl := l.copy()
+
+	sl.name = name
+
+	return l.subloggerHook(sl)
+}
+
+// Update the logging level on-the-fly. This will affect all subloggers as
+// well, unless they were created with IndependentLevels. The handler may
+// still filter out entries at levels it does not have enabled.
+func (l *slogLogger) SetLevel(level Level) {
+	atomic.StoreInt32(l.level, int32(level))
+}
+
+// Returns the current level
+func (l *slogLogger) GetLevel() Level {
+	if l.levelDirectives != nil {
+		if level := l.levelDirectives.cachedLevel(l.directiveCache, l.directiveRoot, l.name); level != NoLevel {
+			return level
+		}
+	}
+
+	return Level(atomic.LoadInt32(l.level))
+}
+
+// Create a *log.Logger that will send it's data through this Logger.
+func (l *slogLogger) StandardLogger(opts *StandardLoggerOptions) *log.Logger {
+	if opts == nil {
+		opts = &StandardLoggerOptions{}
+	}
+
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+func (l *slogLogger) StandardWriter(opts *StandardLoggerOptions) io.Writer {
+	newLog := *l
+	if l.callerOffset > 0 {
+		// See intLogger.StandardWriter for the frames being skipped.
+		newLog.callerOffset = l.callerOffset + 4
+	}
+	return &stdlogAdapter{
+		log:                      &newLog,
+		inferLevels:              opts.InferLevels,
+		inferLevelsWithTimestamp: opts.InferLevelsWithTimestamp,
+		forceLevel:               opts.ForceLevel,
+	}
+}
+
+// copy returns a shallow copy of the slogLogger, replacing the level pointer
+// when necessary
+func (l *slogLogger) copy() *slogLogger {
+	sl := *l
+
+	// The copy may be renamed, so it can't share the cached directive level.
+	sl.directiveCache = new(atomic.Uint64)
+
+	if l.independentLevels {
+		sl.level = new(int32)
+		*sl.level = *l.level
+	}
+
+	return &sl
+}
@@ -0,0 +1,225 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("conforms to slogtest", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		newHandler := func(t *testing.T) slog.Handler {
+			buf.Reset()
+			return NewSlogHandler(New(&LoggerOptions{
+				Level:      Trace,
+				Output:     &buf,
+				JSONFormat: true,
+			}))
+		}
+
+		result := func(t *testing.T) map[string]any {
+			var raw map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+
+			// Translate our envelope into slog's and expand the dotted group
+			// keys back into nested maps.
+			m := map[string]any{}
+			for k, v := range raw {
+				switch k {
+				case "@timestamp":
+					k = slog.TimeKey
+				case "@level":
+					k = slog.LevelKey
+				case "@message":
+					k = slog.MessageKey
+				}
+
+				parts := strings.Split(k, ".")
+				cur := m
+				for _, p := range parts[:len(parts)-1] {
+					sub, ok := cur[p].(map[string]any)
+					if !ok {
+						sub = map[string]any{}
+						cur[p] = sub
+					}
+					cur = sub
+				}
+				cur[parts[len(parts)-1]] = v
+			}
+
+			return m
+		}
+
+		slogtest.Run(t, newHandler, result)
+	})
+
+	t.Run("maps levels", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Level:  Trace,
+			Output: &buf,
+		})
+
+		sl := slog.New(NewSlogHandler(logger))
+		sl.Log(t.Context(), slog.LevelDebug-4, "trace")
+		sl.Debug("debug")
+		sl.Info("info")
+		sl.Warn("warn")
+		sl.Error("error")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 5)
+
+		for i, want := range []string{"[TRACE]", "[DEBUG]", "[INFO] ", "[WARN] ", "[ERROR]"} {
+			rest := lines[i][strings.IndexByte(lines[i], ' ')+1:]
+			assert.True(t, strings.HasPrefix(rest, want), rest)
+		}
+	})
+
+	t.Run("honors the logger level", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Level:  Warn,
+			Output: &buf,
+		})
+
+		h := NewSlogHandler(logger)
+		assert.False(t, h.Enabled(t.Context(), slog.LevelInfo))
+		assert.True(t, h.Enabled(t.Context(), slog.LevelWarn))
+
+		slog.New(h).Info("dropped")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("flattens groups and uses the logger name", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:        "app",
+			Output:      &buf,
+			DisableTime: true,
+		})
+
+		sl := slog.New(NewSlogHandler(logger)).
+			With(SlogModuleKey, "http").
+			WithGroup("req").
+			With("id", 1)
+		sl.Info("request", slog.Group("resp", "status", 200))
+
+		assert.Equal(t, "[INFO]  app.http: request: req.id=1 req.resp.status=200\n", buf.String())
+	})
+
+	t.Run("uses the record time", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Output:     &buf,
+			TimeFormat: time.Kitchen,
+		})
+
+		h := NewSlogHandler(logger)
+		r := slog.NewRecord(time.Date(1970, time.January, 1, 13, 4, 0, 0, time.UTC), slog.LevelInfo, "test", 0)
+		require.NoError(t, h.Handle(t.Context(), r))
+
+		assert.Equal(t, "1:04PM [INFO]  test\n", buf.String())
+	})
+}
+
+func TestFromSlogHandler(t *testing.T) {
+	t.Run("writes records to the handler", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := FromSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug - 4,
+		}), &LoggerOptions{
+			Name:  "app",
+			Level: Trace,
+		})
+
+		logger.Named("http").With("id", 1).Trace("request",
+			"path", "/", "bytes", Fmt("%d KiB", 4), "err", errors.New("boom"))
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+
+		assert.Equal(t, "DEBUG-4", raw["level"])
+		assert.Equal(t, "request", raw["msg"])
+		assert.Equal(t, "app.http", raw[SlogModuleKey])
+		assert.Equal(t, float64(1), raw["id"])
+		assert.Equal(t, "/", raw["path"])
+		assert.Equal(t, "4 KiB", raw["bytes"])
+		assert.Equal(t, "boom", raw["err"])
+	})
+
+	t.Run("handles missing keys and stacktraces", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := FromSlogHandler(slog.NewJSONHandler(&buf, nil), nil)
+
+		logger.Info("extra", "a", "b", "c")
+		logger.Info("trace", "a", "b", CapturedStacktrace("stack"))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &raw))
+		assert.Equal(t, "c", raw[MissingKey])
+
+		raw = nil
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &raw))
+		assert.Equal(t, "stack", raw["stacktrace"])
+	})
+
+	t.Run("respects the logger and handler levels", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := FromSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			Level: slog.LevelWarn,
+		}), &LoggerOptions{
+			Level: Debug,
+		})
+
+		assert.False(t, logger.IsDebug())
+		assert.True(t, logger.IsWarn())
+
+		logger.Info("dropped")
+		assert.Empty(t, buf.String())
+
+		logger.SetLevel(Error)
+		logger.Warn("dropped")
+		assert.Empty(t, buf.String())
+
+		logger.Error("kept")
+		assert.Contains(t, buf.String(), "msg=kept")
+	})
+
+	t.Run("round trips through NewSlogHandler", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		inner := New(&LoggerOptions{
+			Output:      &buf,
+			DisableTime: true,
+		})
+
+		logger := FromSlogHandler(NewSlogHandler(inner), &LoggerOptions{Name: "app"})
+		logger.Named("http").Warn("hello", "who", "world")
+
+		assert.Equal(t, "[WARN]  app.http: hello: who=world\n", buf.String())
+	})
+}
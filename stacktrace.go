@@ -55,8 +55,6 @@ func takeStacktrace() string {
 	programCounters := _stacktracePool.Get().(*programCounters)
 	defer _stacktracePool.Put(programCounters)
 
-	var buffer bytes.Buffer
-
 	for {
 		// Skip the call to runtime.Counters and takeStacktrace so that the
 		// program counters start at the caller of takeStacktrace.
@@ -70,8 +68,16 @@ func takeStacktrace() string {
 		programCounters = newProgramCounters(len(programCounters.pcs) * 2)
 	}
 
+	return formatStacktrace(programCounters.pcs)
+}
+
+// formatStacktrace renders the stack with the given program counters, as
+// returned by runtime.Callers.
+func formatStacktrace(pcs []uintptr) string {
+	var buffer bytes.Buffer
+
 	i := 0
-	frames := runtime.CallersFrames(programCounters.pcs)
+	frames := runtime.CallersFrames(pcs)
 	for frame, more := frames.Next(); more; frame, more = frames.Next() {
 		if shouldIgnoreStacktraceFunction(frame.Function) {
 			continue
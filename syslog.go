@@ -0,0 +1,298 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyslogProtocol selects the syslog message format.
+type SyslogProtocol uint8
+
+const (
+	// SyslogRFC5424 is the current syslog protocol, with args encoded as
+	// STRUCTURED-DATA.
+	SyslogRFC5424 SyslogProtocol = iota
+
+	// SyslogRFC3164 is the older BSD syslog format, which has no structured
+	// data, so args are appended to the message as key=value pairs.
+	SyslogRFC3164
+)
+
+// SyslogFacility is the facility a syslog message is logged under. The kernel
+// facility is not available to user processes.
+type SyslogFacility uint8
+
+const (
+	SyslogUser SyslogFacility = iota + 1
+	SyslogMail
+	SyslogDaemon
+	SyslogAuth
+	SyslogSyslog
+	SyslogLPR
+	SyslogNews
+	SyslogUUCP
+	SyslogCron
+	SyslogAuthPriv
+	SyslogFTP
+
+	// The local facilities start at 16.
+	SyslogLocal0 SyslogFacility = iota + 5
+	SyslogLocal1
+	SyslogLocal2
+	SyslogLocal3
+	SyslogLocal4
+	SyslogLocal5
+	SyslogLocal6
+	SyslogLocal7
+)
+
+// DefaultSyslogStructuredDataID is the SD-ID args are encoded under if no
+// other is given. 32473 is the private enterprise number reserved for use in
+// documentation.
+const DefaultSyslogStructuredDataID = "hclog@32473"
+
+// SyslogOptions configures the OutputSyslog format.
+type SyslogOptions struct {
+	// Protocol is the message format. Defaults to SyslogRFC5424.
+	Protocol SyslogProtocol
+
+	// Facility of every message. Defaults to SyslogUser.
+	Facility SyslogFacility
+
+	// Hostname to identify the machine by. Defaults to os.Hostname.
+	Hostname string
+
+	// AppName identifies the application. If set, the full name of the
+	// logger is used as the MSGID. Otherwise the first component of the name
+	// is used as the APP-NAME and the rest as the MSGID, and if the logger
+	// has no name the program name is used.
+	AppName string
+
+	// StructuredDataID is the SD-ID args are encoded under with
+	// SyslogRFC5424. Defaults to DefaultSyslogStructuredDataID.
+	StructuredDataID string
+}
+
+// syslogFormat is the resolved SyslogOptions for a logger.
+type syslogFormat struct {
+	protocol SyslogProtocol
+	facility SyslogFacility
+	hostname string
+	appName  string
+	procID   string
+	sdID     string
+}
+
+func newSyslogFormat(opts *SyslogOptions) *syslogFormat {
+	if opts == nil {
+		opts = &SyslogOptions{}
+	}
+
+	f := &syslogFormat{
+		protocol: opts.Protocol,
+		facility: opts.Facility,
+		hostname: opts.Hostname,
+		appName:  opts.AppName,
+		procID:   strconv.Itoa(os.Getpid()),
+		sdID:     opts.StructuredDataID,
+	}
+
+	if f.facility == 0 {
+		f.facility = SyslogUser
+	}
+
+	if f.hostname == "" {
+		f.hostname, _ = os.Hostname()
+	}
+
+	if f.sdID == "" {
+		f.sdID = DefaultSyslogStructuredDataID
+	}
+
+	return f
+}
+
+// syslogSeverity maps a level to a syslog severity.
+func syslogSeverity(level Level) int {
+	switch level {
+	case Error:
+		return 3 // err
+	case Warn:
+		return 4 // warning
+	case Info:
+		return 6 // info
+	case Debug, Trace:
+		return 7 // debug
+	default:
+		return 5 // notice
+	}
+}
+
+// names returns the APP-NAME and MSGID for a logger name.
+func (f *syslogFormat) names(name string) (appName, msgID string) {
+	if f.appName != "" {
+		return f.appName, name
+	}
+
+	if name == "" {
+		return filepath.Base(os.Args[0]), ""
+	}
+
+	appName, msgID, _ = strings.Cut(name, ".")
+	return appName, msgID
+}
+
+// logSyslog writes the entry as a syslog message. Only the header fields are
+// limited to printable ASCII, the message and values are written as UTF-8.
+func (l *intLogger) logSyslog(t time.Time, pc uintptr, name string, level Level, msg string, implied, args []any) {
+	f := l.syslog
+
+	pairs, stacktrace := splitArgs(implied, args)
+
+	if l.callerOffset > 0 {
+		if file, line, ok := callerLocation(pc); ok {
+			pairs = append([]any{"caller", file + ":" + strconv.Itoa(line)}, pairs...)
+		}
+	}
+
+	appName, msgID := f.names(name)
+
+	_ = l.writer.WriteByte('<')
+	_, _ = l.writer.WriteString(strconv.Itoa(int(f.facility)*8 + syslogSeverity(level)))
+	_ = l.writer.WriteByte('>')
+
+	if f.protocol == SyslogRFC3164 {
+		if t.IsZero() {
+			t = time.Now()
+		}
+		_, _ = l.writer.WriteString(t.Format(time.Stamp))
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(syslogHeaderField(f.hostname, 255))
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(syslogHeaderField(appName, 32))
+		_ = l.writer.WriteByte('[')
+		_, _ = l.writer.WriteString(f.procID)
+		_, _ = l.writer.WriteString("]:")
+
+		if msgID != "" {
+			_ = l.writer.WriteByte(' ')
+			_, _ = l.writer.WriteString(msgID)
+			_ = l.writer.WriteByte(':')
+		}
+
+		if msg != "" {
+			_ = l.writer.WriteByte(' ')
+			_, _ = l.writer.WriteString(msg)
+		}
+
+		for i := 0; i < len(pairs); i += 2 {
+			val, raw := l.plainValue(pairs[i+1])
+			if !raw && (needsQuoting(val) || strings.ContainsAny(val, " \"=")) {
+				val = strconv.Quote(val)
+			}
+
+			_ = l.writer.WriteByte(' ')
+			_, _ = l.writer.WriteString(argKey(pairs[i]))
+			_ = l.writer.WriteByte('=')
+			_, _ = l.writer.WriteString(val)
+		}
+	} else {
+		_, _ = l.writer.WriteString("1 ")
+		if l.disableTime || t.IsZero() {
+			_ = l.writer.WriteByte('-')
+		} else {
+			_, _ = l.writer.WriteString(t.Format("2006-01-02T15:04:05.000000Z07:00"))
+		}
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(syslogHeaderField(f.hostname, 255))
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(syslogHeaderField(appName, 48))
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(f.procID)
+		_ = l.writer.WriteByte(' ')
+		_, _ = l.writer.WriteString(syslogHeaderField(msgID, 32))
+		_ = l.writer.WriteByte(' ')
+
+		if len(pairs) == 0 {
+			_ = l.writer.WriteByte('-')
+		} else {
+			_ = l.writer.WriteByte('[')
+			_, _ = l.writer.WriteString(f.sdID)
+			for i := 0; i < len(pairs); i += 2 {
+				val, _ := l.plainValue(pairs[i+1])
+
+				_ = l.writer.WriteByte(' ')
+				_, _ = l.writer.WriteString(syslogParamName(argKey(pairs[i])))
+				_, _ = l.writer.WriteString(`="`)
+				writeSyslogParamValue(l.writer, val)
+				_ = l.writer.WriteByte('"')
+			}
+			_ = l.writer.WriteByte(']')
+		}
+
+		if msg != "" || stacktrace != "" {
+			_ = l.writer.WriteByte(' ')
+			_, _ = l.writer.WriteString(msg)
+		}
+	}
+
+	if stacktrace != "" {
+		_ = l.writer.WriteByte('\n')
+		_, _ = l.writer.WriteString(string(stacktrace))
+	}
+
+	_ = l.writer.WriteByte('\n')
+}
+
+// syslogHeaderField returns s made safe for use as a header field, which is
+// limited to printable ASCII, has no spaces and uses "-" when empty.
+func syslogHeaderField(s string, max int) string {
+	return syslogToken(s, max, "")
+}
+
+// syslogParamName returns s made safe for use as an SD-NAME.
+func syslogParamName(s string) string {
+	return syslogToken(s, 32, `="]`)
+}
+
+func syslogToken(s string, max int, exclude string) string {
+	if s == "" {
+		return "-"
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s) && sb.Len() < max; i++ {
+		c := s[i]
+		if c < 33 || c > 126 || strings.IndexByte(exclude, c) != -1 {
+			c = '_'
+		}
+		_ = sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+// writeSyslogParamValue writes s as a PARAM-VALUE, escaping the characters
+// which would otherwise end it, and line breaks as \n and \r so that the
+// entry stays on one line.
+func writeSyslogParamValue(w *writer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\', ']':
+			_ = w.WriteByte('\\')
+			_ = w.WriteByte(c)
+		case '\n':
+			_, _ = w.WriteString(`\n`)
+		case '\r':
+			_, _ = w.WriteString(`\r`)
+		default:
+			_ = w.WriteByte(c)
+		}
+	}
+}
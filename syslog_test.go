@@ -0,0 +1,95 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Syslog(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 891234000, time.UTC)
+	timeFn := func() time.Time { return ts }
+
+	t.Run("formats RFC 5424 messages", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:         "app",
+			Output:       &buf,
+			OutputFormat: OutputSyslog,
+			TimeFn:       timeFn,
+			Syslog: &SyslogOptions{
+				Hostname: "host1",
+			},
+		})
+
+		logger.Named("http").With("peer", "10.0.0.1").Warn("slow request", "path", "/a b", "quoted", `x"]\y`, "body", "one\r\ntwo")
+		logger.Info("started")
+		logger.Error("failed", "error", errors.New("boom"), CapturedStacktrace("stack"))
+
+		assert.Equal(t,
+			"<12>1 2026-03-04T05:06:07.891234Z host1 app "+pid+" http "+
+				`[hclog@32473 peer="10.0.0.1" path="/a b" quoted="x\"\]\\y" body="one\r\ntwo"] slow request`+"\n"+
+				"<14>1 2026-03-04T05:06:07.891234Z host1 app "+pid+" - - started\n"+
+				"<11>1 2026-03-04T05:06:07.891234Z host1 app "+pid+` - [hclog@32473 error="boom"] failed`+"\nstack\n",
+			buf.String())
+	})
+
+	t.Run("uses the app name and facility", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:         "raft",
+			Level:        Trace,
+			Output:       &buf,
+			OutputFormat: OutputSyslog,
+			DisableTime:  true,
+			Syslog: &SyslogOptions{
+				Facility:         SyslogLocal3,
+				Hostname:         "my host",
+				AppName:          "consul",
+				StructuredDataID: "consul@32473",
+			},
+		})
+
+		logger.Trace("tick", "bad key=", 1)
+
+		assert.Equal(t,
+			"<159>1 - my_host consul "+pid+` raft [consul@32473 bad_key_="1"] tick`+"\n",
+			buf.String())
+	})
+
+	t.Run("formats RFC 3164 messages", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := New(&LoggerOptions{
+			Name:         "app",
+			Output:       &buf,
+			OutputFormat: OutputSyslog,
+			TimeFn:       timeFn,
+			Syslog: &SyslogOptions{
+				Protocol: SyslogRFC3164,
+				Facility: SyslogDaemon,
+				Hostname: "host1",
+			},
+		})
+
+		logger.Named("http").Info("request", "path", "/a b", "status", 200)
+		logger.Debug("hidden")
+		logger.Error("failed")
+
+		assert.Equal(t,
+			"<30>Mar  4 05:06:07 host1 app["+pid+`]: http: request path="/a b" status=200`+"\n"+
+				"<27>Mar  4 05:06:07 host1 app["+pid+"]: failed\n",
+			buf.String())
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// syslogSocketPaths are where the local syslog daemon is commonly found.
+var syslogSocketPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogWriter sends each write to a syslog server as a single message, for
+// use as LoggerOptions.Output together with OutputSyslog. The logger writes
+// each entry with a single call, so an entry always becomes one message.
+//
+// With the "unixgram" and "udp" networks every message is sent as its own
+// datagram. With "tcp" messages are framed using octet counting, as described
+// in RFC 6587, so that messages spanning several lines are delimited
+// correctly.
+type SyslogWriter struct {
+	network string
+	addr    string
+
+	mu   sync.Mutex
+	conn net.Conn
+	buf  bytes.Buffer
+}
+
+// NewSyslogWriter connects to the syslog server at addr. network is one of
+// "unixgram", "udp", "udp4", "udp6", "tcp", "tcp4" or "tcp6". If network and
+// addr are both empty, the local syslog daemon is used.
+func NewSyslogWriter(network, addr string) (*SyslogWriter, error) {
+	switch network {
+	case "":
+		if addr != "" {
+			return nil, errors.New("syslog network is required with an address")
+		}
+	case "unixgram", "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", network)
+	}
+
+	w := &SyslogWriter{
+		network: network,
+		addr:    addr,
+	}
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// connect dials the server. The mutex must be held, except from the
+// constructor.
+func (w *SyslogWriter) connect() error {
+	if w.network != "" {
+		conn, err := net.Dial(w.network, w.addr)
+		if err != nil {
+			return err
+		}
+
+		w.conn = conn
+		return nil
+	}
+
+	var err error
+	for _, path := range syslogSocketPaths {
+		var conn net.Conn
+		if conn, err = net.Dial("unixgram", path); err == nil {
+			w.conn = conn
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unable to connect to local syslog: %w", err)
+}
+
+func (w *SyslogWriter) framed() bool {
+	switch w.network {
+	case "tcp", "tcp4", "tcp6":
+		return true
+	default:
+		return false
+	}
+}
+
+// Write sends p as a single message, without its trailing newline. If sending
+// fails the connection is re-established and the message sent again once.
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg := bytes.TrimSuffix(p, []byte("\n"))
+
+	w.buf.Reset()
+	if w.framed() {
+		w.buf.WriteString(strconv.Itoa(len(msg)))
+		w.buf.WriteByte(' ')
+	}
+	w.buf.Write(msg)
+
+	if w.conn != nil {
+		if _, err := w.conn.Write(w.buf.Bytes()); err == nil {
+			return len(p), nil
+		}
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+
+	if _, err := w.conn.Write(w.buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection to the server.
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogWriter(t *testing.T) {
+	newLogger := func(w *SyslogWriter) Logger {
+		return New(&LoggerOptions{
+			Name:         "app",
+			Output:       w,
+			OutputFormat: OutputSyslog,
+			DisableTime:  true,
+			Syslog: &SyslogOptions{
+				Hostname: "host1",
+			},
+		})
+	}
+
+	readPacket := func(t *testing.T, conn net.PacketConn) string {
+		t.Helper()
+
+		buf := make([]byte, 4096)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		return string(buf[:n])
+	}
+
+	t.Run("sends datagrams over udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		w, err := NewSyslogWriter("udp", conn.LocalAddr().String())
+		require.NoError(t, err)
+		defer w.Close()
+
+		logger := newLogger(w)
+		logger.Info("one", "a", 1)
+		logger.Warn("two\nlines")
+
+		assert.True(t, strings.HasPrefix(readPacket(t, conn), "<14>1 - host1 app "))
+		msg := readPacket(t, conn)
+		assert.True(t, strings.HasPrefix(msg, "<12>1 - host1 app "))
+		assert.True(t, strings.HasSuffix(msg, " - - two\nlines"))
+	})
+
+	t.Run("sends datagrams over unixgram", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log.sock")
+
+		conn, err := net.ListenPacket("unixgram", path)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		w, err := NewSyslogWriter("unixgram", path)
+		require.NoError(t, err)
+		defer w.Close()
+
+		newLogger(w).Error("failed", "error", "boom")
+
+		msg := readPacket(t, conn)
+		assert.True(t, strings.HasPrefix(msg, "<11>1 - host1 app "))
+		assert.True(t, strings.HasSuffix(msg, ` - [hclog@32473 error="boom"] failed`))
+	})
+
+	t.Run("frames messages over tcp", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		w, err := NewSyslogWriter("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer w.Close()
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		logger := newLogger(w)
+		logger.Info("one")
+		logger.Info("two\nlines", CapturedStacktrace("stack"))
+
+		// Read each message using its octet count.
+		r := bufio.NewReader(conn)
+		for _, want := range []string{" - - one", " - - two\nlines\nstack"} {
+			count, err := r.ReadString(' ')
+			require.NoError(t, err)
+
+			n, err := strconv.Atoi(strings.TrimSuffix(count, " "))
+			require.NoError(t, err)
+
+			msg := make([]byte, n)
+			_, err = io.ReadFull(r, msg)
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(string(msg), "<14>1 - host1 app "))
+			assert.True(t, strings.HasSuffix(string(msg), want), string(msg))
+		}
+	})
+
+	t.Run("rejects unknown networks", func(t *testing.T) {
+		_, err := NewSyslogWriter("ip", "127.0.0.1")
+		assert.EqualError(t, err, `unsupported syslog network "ip"`)
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright IBM Corp. 2017, 2026
+// SPDX-License-Identifier: MIT
+
+package hclog
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// maxLogValuerDepth limits how many times a value is resolved, in case a
+// LogValuer returns itself.
+const maxLogValuerDepth = 100
+
+// LogValuer is implemented by values which resolve themselves when an entry
+// is written, rather than when it's logged. The value returned by LogValue
+// is written in place of the LogValuer, and may itself be a LogValuer or a
+// Field created by Group, which nests its args under the key of the value:
+//
+//	func (u *User) LogValue() any {
+//		return hclog.Group("", "id", u.ID, "name", u.Name)
+//	}
+//
+// A slog.LogValuer is resolved in the same way, so a type can implement
+// either one. LogValue is only called for entries which pass the level
+// check, Exclude and sampling.
+type LogValuer interface {
+	LogValue() any
+}
+
+// Lazy is a value computed only if the entry it's given with is written,
+// which avoids the cost of computing it for an entry below the level of the
+// logger:
+//
+//	logger.Debug("state", "peers", hclog.Lazy(func() any {
+//		return expensivePeerList()
+//	}))
+//
+// A Lazy given to With is computed again for each entry.
+type Lazy func() any
+
+// LogValue implements LogValuer.
+func (f Lazy) LogValue() any {
+	return f()
+}
+
+// isLogValuer reports whether val needs to be resolved with resolveValue.
+func isLogValuer(val any) bool {
+	switch st := val.(type) {
+	case Redacted:
+		return false
+	case LogValuer, slog.LogValuer:
+		return true
+	case Field:
+		return st.kind == fieldAny && isLogValuer(st.val)
+	default:
+		return false
+	}
+}
+
+// resolveValue returns the value which val resolves to, if it's a LogValuer
+// or a slog.LogValuer, or a Field holding one. Otherwise val is returned as
+// is.
+func resolveValue(val any) any {
+	for range maxLogValuerDepth {
+		if !isLogValuer(val) {
+			return val
+		}
+
+		switch st := val.(type) {
+		case Field:
+			val = st.val
+		case LogValuer:
+			val = st.LogValue()
+		case slog.LogValuer:
+			val = slogValueArg(st.LogValue().Resolve())
+		}
+	}
+
+	return val
+}
+
+// slogValueArg converts a resolved slog.Value into the value of an arg, with
+// a group becoming a Field created by Group.
+func slogValueArg(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	attrs := v.Group()
+
+	args := make([]any, 0, 2*len(attrs))
+	for _, a := range attrs {
+		args = append(args, a.Key, slogValueArg(a.Value.Resolve()))
+	}
+
+	return Group("", args...)
+}
+
+// resolveArgs returns args with the LogValuers among their values resolved,
+// including those nested in groups, and whether any were. A Field keeps its
+// key. args is returned as is if there's nothing to resolve.
+func resolveArgs(args []any) ([]any, bool) {
+	var resolved []any
+
+	for i := 0; i < len(args); {
+		key, val, next := nextArg(args, i)
+
+		changed := isLogValuer(val)
+		if changed {
+			val = resolveValue(val)
+		}
+
+		if f, ok := val.(Field); ok {
+			if group, ok := f.group(); ok {
+				if group, ok := resolveArgs(group); ok {
+					val, changed = Group(f.Key, group...), true
+				}
+			}
+		}
+
+		if changed {
+			if resolved == nil {
+				resolved = slices.Clone(args)
+			}
+
+			if _, ok := args[i].(Field); ok && next == i+1 {
+				resolved[i] = keyedValue(key, val)
+			} else {
+				resolved[next-1] = val
+			}
+		}
+
+		i = next
+	}
+
+	if resolved == nil {
+		return args, false
+	}
+
+	return resolved, true
+}
+
+// keyedValue returns a Field for val with the given key, keeping the kind of
+// val if it's already a Field.
+func keyedValue(key string, val any) Field {
+	if f, ok := val.(Field); ok {
+		f.Key = key
+		return f
+	}
+
+	return Any(key, val)
+}